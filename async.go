@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// awOpt defines a typed functional option interface for an AsyncWriter
+type awOpt interface {
+	setOption(*asyncWriter)
+}
+
+// AsyncQueue is the number of writes that can be waiting to be written
+type AsyncQueue int
+
+// asyncPolicy is what a write does when the queue is full
+type asyncPolicy uint8
+
+// The policies for a full AsyncWriter queue
+const (
+	AsyncBlock      asyncPolicy = iota // wait for room in the queue
+	AsyncDropNewest                    // drop the write
+	AsyncDropOldest                    // drop the oldest write in the queue to make room
+)
+
+// setOption satisfies the functional option interface for an AsyncWriter
+func (n AsyncQueue) setOption(aw *asyncWriter) { aw.size = int(n) }
+
+// setOption satisfies the functional option interface for an AsyncWriter
+func (p asyncPolicy) setOption(aw *asyncWriter) { aw.policy = p }
+
+// AsyncWriter is a helper function that writes to w from a goroutine, so a slow writer (i.e. a
+// NetWriter) doesn't hold up the log functions. The writes wait in a queue of AsyncQueue writes
+// (1024 by default), and when it's full the policy (AsyncBlock by default) decides if the write
+// waits or a write is dropped, the dropped writes are counted. Each write is copied, so a write
+// has returned before it's written and any write error is not returned. The writer is kept with
+// the logger's closers, and closing it writes everything in the queue before closing w.
+func AsyncWriter(w io.Writer, opts ...awOpt) BufferedWriter {
+	aw := &asyncWriter{w: w, size: 1024, done: make(chan struct{})}
+	for _, opt := range opts {
+		opt.setOption(aw)
+	}
+	aw.queue = make(chan asyncWrite, aw.size)
+	go aw.run()
+	return aw
+}
+
+// asyncWrite is a copy of a write, or a Flush marker that is closed once it's reached
+type asyncWrite struct {
+	p     []byte
+	flush chan struct{}
+}
+
+// asyncWriter the underling struct that queues the writes
+type asyncWriter struct {
+	w      io.Writer
+	size   int
+	policy asyncPolicy
+
+	queue   chan asyncWrite
+	done    chan struct{} // closed when run has written everything after a Close
+	dropped uint64
+
+	fm      sync.Mutex      // guards flushes, which is written by drop and read by run
+	flushes []chan struct{} // the Flush markers taken out of the queue by drop, for run to close
+
+	m      sync.RWMutex // a write holds a read lock, so Close doesn't close the queue during a write
+	closed bool
+}
+
+// Write queues a copy of p to be written
+func (aw *asyncWriter) Write(p []byte) (n int, err error) {
+	aw.m.RLock()
+	defer aw.m.RUnlock()
+
+	if aw.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	var item = asyncWrite{p: append([]byte(nil), p...)}
+	switch aw.policy {
+	case AsyncBlock:
+		aw.queue <- item
+	case AsyncDropNewest:
+		select {
+		case aw.queue <- item:
+		default:
+			atomic.AddUint64(&aw.dropped, 1)
+		}
+	case AsyncDropOldest:
+		for sent := false; !sent; {
+			select {
+			case aw.queue <- item:
+				sent = true
+			default:
+				aw.drop()
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// drop takes the oldest write out of the queue. A Flush marker is passed to run, as
+// run may still be writing the write before it.
+func (aw *asyncWriter) drop() {
+	select {
+	case old := <-aw.queue:
+		if old.flush != nil {
+			aw.fm.Lock()
+			aw.flushes = append(aw.flushes, old.flush)
+			aw.fm.Unlock()
+			return
+		}
+		atomic.AddUint64(&aw.dropped, 1)
+	default:
+	}
+}
+
+// run writes everything from the queue until the queue is closed
+func (aw *asyncWriter) run() {
+	defer close(aw.done)
+	defer aw.release()
+	for item := range aw.queue {
+		aw.release() // the write before item is done
+		if item.flush != nil {
+			close(item.flush)
+			continue
+		}
+		aw.w.Write(item.p)
+	}
+}
+
+// release closes the Flush markers that were dropped, only run calls it
+// so they are closed after the write that was in progress is done
+func (aw *asyncWriter) release() {
+	aw.fm.Lock()
+	defer aw.fm.Unlock()
+	for _, flush := range aw.flushes {
+		close(flush)
+	}
+	aw.flushes = nil
+}
+
+// Flush waits until everything that was queued before it has been written, or ctx is done
+func (aw *asyncWriter) Flush(ctx context.Context) error {
+	aw.m.RLock()
+	if aw.closed {
+		aw.m.RUnlock()
+		return nil // Close has already written everything
+	}
+
+	var flush = make(chan struct{})
+	select {
+	case aw.queue <- asyncWrite{flush: flush}:
+		aw.m.RUnlock()
+	case <-ctx.Done():
+		aw.m.RUnlock()
+		return ctx.Err()
+	}
+
+	select {
+	case <-flush:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Dropped returns the number of writes that have been dropped
+func (aw *asyncWriter) Dropped() uint64 { return atomic.LoadUint64(&aw.dropped) }
+
+// Close writes everything in the queue then closes w if it's an io.Closer (that is not
+// a *os.File), any writes after a Close return io.ErrClosedPipe
+func (aw *asyncWriter) Close() error {
+	aw.m.Lock()
+	if !aw.closed {
+		aw.closed = true
+		close(aw.queue)
+	}
+	aw.m.Unlock()
+
+	<-aw.done
+	if c, ok := closer(aw.w); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// unwrap returns the writer that is written to
+func (aw *asyncWriter) unwrap() io.Writer { return aw.w }
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// splitFuncName splits a runtime function name into the package import path and the
+// function name with the package name, i.e. github.com/a/b/db.(*Pool).Get is split into
+// github.com/a/b/db and db.(*Pool).Get
+func splitFuncName(fn string) (pkg, name string) {
+	var i = strings.LastIndexByte(fn, '/') + 1
+	var j = strings.IndexByte(fn[i:], '.')
+	if j < 0 {
+		return fn, fn
+	}
+	return fn[:i+j], fn[i:]
+}
+
+// maxCallers is the most frames that are looked through for the caller of the logger
+const maxCallers = 32
+
+// pkgDir is the directory of this package, a frame from a file in it is part of the logger
+var pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return path.Dir(file)
+}()
+
+// internal returns true for a file of the logger, that is any file in this package that is
+// not a test, or the <autogenerated> file of a method value (i.e. log.Info passed as a func)
+func internal(file string) bool {
+	if file == "<autogenerated>" {
+		return true
+	}
+	return path.Dir(file) == pkgDir && !strings.HasSuffix(file, "_test.go")
+}
+
+// mainModule is the module path of the main module, it's used to trim the file names
+// of a binary that was built with -trimpath (i.e. github.com/a/b/db/pool.go)
+var mainModule = struct {
+	once sync.Once
+	path string
+}{}
+
+// modRoots caches the module root directory for each source directory, an empty root
+// means the directory is not in a module
+var modRoots sync.Map
+
+// relFile returns file relative to the root of its module, i.e. /src/b/internal/db/pool.go is
+// internal/db/pool.go when /src/b has the go.mod file. A file without a module is returned as is.
+func relFile(file string) string {
+	mainModule.once.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			mainModule.path = info.Main.Path
+		}
+	})
+	if mainModule.path != "" && strings.HasPrefix(file, mainModule.path+"/") {
+		return file[len(mainModule.path)+1:]
+	}
+
+	var dir = path.Dir(file)
+	root, ok := modRoots.Load(dir)
+	if !ok {
+		root, _ = modRoots.LoadOrStore(dir, modRoot(dir))
+	}
+	if r := root.(string); r != "" {
+		return strings.TrimPrefix(file, r+"/")
+	}
+	return file
+}
+
+// modRoot returns the first directory up from dir that has a go.mod file, or empty
+func modRoot(dir string) string {
+	for {
+		if _, err := os.Stat(dir + "/go.mod"); err == nil {
+			return dir
+		}
+		var parent = path.Dir(dir)
+		if parent == dir || parent == "." {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -1,6 +1,16 @@
 package color
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+)
+
+// Color is a foreground color that can also be used as the background of a Style. It is
+// implemented by Foreground (the eight basic and bright colors), Color256 and RGB.
+type Color interface {
+	ToESC() string
+	sgr(bg bool) string // the SGR parameters for the color, i.e. 31 or 38;5;208
+}
 
 // Foreground graphics modes
 type Foreground int
@@ -20,10 +30,118 @@ const (
 	White
 )
 
+// The bright (high intensity) foreground graphics modes
+const (
+	BrightBlack Foreground = (iota + 90)
+	BrightRed
+	BrightGreen
+	BrightYellow
+	BrightBlue
+	BrightMagenta
+	BrightCyan
+	BrightWhite
+)
+
 // ToESC returns the escape sequence based on the passed in int
-func (f Foreground) ToESC() string {
+func (f Foreground) ToESC() string { return esc(f.sgr(false)) }
+
+func (f Foreground) sgr(bg bool) string {
 	if int(f) < 0 {
 		return ""
 	}
-	return "\x1b[" + strconv.Itoa(int(f)) + "m"
+	if bg {
+		return strconv.Itoa(int(f) + 10) // the background codes are 10 more
+	}
+	return strconv.Itoa(int(f))
+}
+
+// Color256 is one of the 256 colors of the xterm palette
+type Color256 uint8
+
+// ToESC returns the escape sequence for the color
+func (c Color256) ToESC() string { return esc(c.sgr(false)) }
+
+func (c Color256) sgr(bg bool) string {
+	if bg {
+		return "48;5;" + strconv.Itoa(int(c))
+	}
+	return "38;5;" + strconv.Itoa(int(c))
+}
+
+// RGB is a 24-bit (truecolor) color
+type RGB struct{ R, G, B uint8 }
+
+// ToESC returns the escape sequence for the color
+func (c RGB) ToESC() string { return esc(c.sgr(false)) }
+
+func (c RGB) sgr(bg bool) string {
+	var mode = "38;2;"
+	if bg {
+		mode = "48;2;"
+	}
+	return mode + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B))
+}
+
+// Attribute is a text attribute, they can be combined i.e. Bold|Underline
+type Attribute uint8
+
+// The text attributes defined
+const (
+	Bold Attribute = 1 << iota
+	Dim
+	Italic
+	Underline
+	Blink
+	Reverse
+)
+
+// the SGR codes for each attribute bit in order
+var attrCodes = []string{"1", "2", "3", "4", "5", "7"}
+
+// ToESC returns the escape sequence for the attributes
+func (a Attribute) ToESC() string { return esc(a.sgr()) }
+
+func (a Attribute) sgr() string {
+	var codes []string
+	for i, code := range attrCodes {
+		if a&(1<<uint(i)) != 0 {
+			codes = append(codes, code)
+		}
+	}
+	return strings.Join(codes, ";")
+}
+
+// Style is a foreground and background color with text attributes, any part
+// can be left empty. i.e. Style{Fg: White, Bg: Red, Attr: Bold}
+type Style struct {
+	Fg   Color
+	Bg   Color
+	Attr Attribute
+}
+
+// ToESC returns a single escape sequence for the whole style
+func (s Style) ToESC() string {
+	var params []string
+	if p := s.Attr.sgr(); p != "" {
+		params = append(params, p)
+	}
+	if s.Fg != nil {
+		if p := s.Fg.sgr(false); p != "" {
+			params = append(params, p)
+		}
+	}
+	if s.Bg != nil {
+		if p := s.Bg.sgr(true); p != "" {
+			params = append(params, p)
+		}
+	}
+	return esc(strings.Join(params, ";"))
+}
+
+// esc wraps the SGR parameters in an escape sequence, no parameters is no sequence
+func esc(params string) string {
+	if params == "" {
+		return ""
+	}
+	return "\x1b[" + params + "m"
 }
@@ -0,0 +1,30 @@
+package color
+
+import "testing"
+
+func TestToESC(t *testing.T) {
+	tests := []struct {
+		name  string
+		color interface{ ToESC() string }
+		want  string
+	}{
+		{name: "foreground", color: Red, want: "\x1b[31m"},
+		{name: "bright foreground", color: BrightCyan, want: "\x1b[96m"},
+		{name: "no color", color: NoColor, want: ""},
+		{name: "256 color", color: Color256(208), want: "\x1b[38;5;208m"},
+		{name: "truecolor", color: RGB{255, 136, 0}, want: "\x1b[38;2;255;136;0m"},
+		{name: "attributes", color: Bold | Underline, want: "\x1b[1;4m"},
+		{name: "style", color: Style{Fg: White, Bg: Red, Attr: Bold}, want: "\x1b[1;37;41m"},
+		{name: "style 256 and truecolor", color: Style{Fg: Color256(15), Bg: RGB{1, 2, 3}}, want: "\x1b[38;5;15;48;2;1;2;3m"},
+		{name: "style background only", color: Style{Bg: Color256(52), Attr: Dim}, want: "\x1b[2;48;5;52m"},
+		{name: "style no color", color: Style{Fg: NoColor}, want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			if have := test.color.ToESC(); have != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have, test.want)
+			}
+		})
+	}
+}
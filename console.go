@@ -0,0 +1,297 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/njones/logger/color"
+	"github.com/njones/logger/kv"
+)
+
+var cPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// consoleOpt defines a typed functional option interface for the ConsoleEncoder
+type consoleOpt interface {
+	setOption(*consoleEncoder)
+}
+
+// ConsoleStyles are the styles the ConsoleEncoder uses for each part of the line, a
+// zero Style leaves that part plain. It can be passed in to ConsoleEncoder as an option.
+type ConsoleStyles struct {
+	Time   color.Style
+	Caller color.Style
+	Key    color.Style
+	Value  color.Style
+	Error  color.Style // the value of an error, or of an `err` or `error` key
+}
+
+// DefaultConsoleStyles dims the time and caller, has cyan keys and red errors
+var DefaultConsoleStyles = ConsoleStyles{
+	Time:   color.Style{Attr: color.Dim},
+	Caller: color.Style{Attr: color.Dim},
+	Key:    color.Style{Fg: color.Cyan},
+	Error:  color.Style{Fg: color.Red},
+}
+
+// setOption satisfies the functional option interface for a ConsoleEncoder
+func (s ConsoleStyles) setOption(enc *consoleEncoder) {
+	enc.style.time = []byte(s.Time.ToESC())
+	enc.style.caller = []byte(s.Caller.ToESC())
+	enc.style.key = []byte(s.Key.ToESC())
+	enc.style.value = []byte(s.Value.ToESC())
+	enc.style.err = []byte(s.Error.ToESC())
+}
+
+// ConsoleLevelWidth pads the level label to n characters, so the messages line up
+type ConsoleLevelWidth int
+
+// ConsoleCallerWidth pads the caller to n characters, so the levels line up
+type ConsoleCallerWidth int
+
+// ConsoleFieldLines writes each K/V pair on its own line, indented under the message,
+// when the line has more than n pairs. Zero keeps all of the pairs on the one line.
+type ConsoleFieldLines int
+
+// setOption satisfies the functional option interface for a ConsoleEncoder
+func (n ConsoleLevelWidth) setOption(enc *consoleEncoder) { enc.level = column{width: int32(n)} }
+
+// setOption satisfies the functional option interface for a ConsoleEncoder
+func (n ConsoleCallerWidth) setOption(enc *consoleEncoder) { enc.caller = column{width: int32(n)} }
+
+// setOption satisfies the functional option interface for a ConsoleEncoder
+func (n ConsoleFieldLines) setOption(enc *consoleEncoder) { enc.fieldLines = int(n) }
+
+// ConsoleEncoder returns an Encoder for reading the log on a terminal. The line is laid out the
+// same as the default encoder, with the K/V pairs in logfmt, but each part of the line has its own
+// style. The level color is only used for the level, prefix and message, so a line without a level
+// (i.e. from Print) still has the other styles. A writer that doesn't get color has no styles.
+func ConsoleEncoder(opts ...consoleOpt) Encoder {
+	enc := &consoleEncoder{append: kv.LogfmtAppender}
+	DefaultConsoleStyles.setOption(enc)
+	for _, opt := range opts {
+		opt.setOption(enc)
+	}
+	return enc
+}
+
+// PrettyEncoder returns a ConsoleEncoder that lines up the columns of each line. The caller and
+// level are padded to the widest one seen so far (unless a width is passed in), a line with more
+// than 3 K/V pairs has each pair on its own line, and multi-line values (i.e. a stack trace) and
+// messages are written on the lines below, indented under the message.
+func PrettyEncoder(opts ...consoleOpt) Encoder {
+	enc := &consoleEncoder{
+		append:     kv.LogfmtAppender,
+		level:      column{auto: true},
+		caller:     column{auto: true},
+		fieldLines: 3,
+		multiline:  true,
+	}
+	DefaultConsoleStyles.setOption(enc)
+	for _, opt := range opts {
+		opt.setOption(enc)
+	}
+	return enc
+}
+
+// consoleEncoder writes a line with a style for each part of it
+type consoleEncoder struct {
+	append *kv.Appender
+
+	level, caller column
+	fieldLines    int
+	multiline     bool // write multi-line values and messages on their own lines
+
+	style struct{ time, caller, key, value, err []byte } // the escape sequences for each part
+}
+
+// column is the width a part of the line is padded to, an auto column grows to the widest part
+type column struct {
+	width int32
+	auto  bool
+}
+
+// padded returns true when the column is padded, even when the part is empty
+func (c *column) padded() bool { return c.auto || atomic.LoadInt32(&c.width) > 0 }
+
+// pad returns the number of spaces needed after a part that is n characters wide
+func (c *column) pad(n int) int {
+	var width = int(atomic.LoadInt32(&c.width))
+	if c.auto && n > width {
+		for !atomic.CompareAndSwapInt32(&c.width, int32(width), int32(n)) {
+			if width = int(atomic.LoadInt32(&c.width)); n <= width {
+				break
+			}
+		}
+		return 0
+	}
+	if n < width {
+		return width - n
+	}
+	return 0
+}
+
+func (*consoleEncoder) encodesTyped() {}
+func (*consoleEncoder) stylesLine()   {}
+
+// consolePair is a single K/V pair, the value is written by the Appender
+type consolePair struct {
+	key   string
+	isErr bool
+	value func(*bytes.Buffer, *kv.Appender)
+}
+
+// pairs returns all of the K/V pairs followed by the typed Event fields
+func (enc *consoleEncoder) pairs(e *Entry) (ps []consolePair, err error) {
+	if len(e.Fields) > 0 {
+		err = kv.Each(fieldList(e.Fields), func(key string, value interface{}) {
+			_, isErr := value.(error)
+			ps = append(ps, consolePair{key, isErr, func(b *bytes.Buffer, a *kv.Appender) { a.Value(b, value) }})
+		})
+	}
+	for _, f := range e.typed {
+		f := f
+		ps = append(ps, consolePair{f.key, f.kind == fErr, func(b *bytes.Buffer, a *kv.Appender) { f.appendText(b, a) }})
+	}
+	return ps, err
+}
+
+// Encode satisfies the Encoder interface
+func (enc *consoleEncoder) Encode(w io.Writer, e *Entry) (err error) {
+	ps, err := enc.pairs(e)
+	if err != nil {
+		return err // nothing has been written yet
+	}
+
+	var b, ok = w.(*bytes.Buffer)
+	if !ok {
+		b = cPool.Get().(*bytes.Buffer)
+		defer func() { b.Reset(); cPool.Put(b) }()
+	}
+
+	var on = e.colored
+	var style = func(esc []byte) func() {
+		if !on || len(esc) == 0 {
+			return func() {}
+		}
+		b.Write(esc)
+		return func() { b.Write(colorEnd) }
+	}
+
+	var col int // the width of the line so far, without any escape sequences
+	if len(e.Stamp) > 0 {
+		end := style(enc.style.time)
+		b.Write(e.Stamp)
+		end()
+		b.Write(space)
+		col += utf8.RuneCount(e.Stamp) + 1
+	}
+	if len(e.Caller) > 0 {
+		end := style(enc.style.caller)
+		b.WriteString(e.Caller)
+		end()
+		n := utf8.RuneCountInString(e.Caller)
+		n += spaces(b, enc.caller.pad(n))
+		if n > 0 {
+			b.Write(space)
+			col += n + 1
+		}
+	}
+	end := style(e.Color)
+	if e.Label != nil || enc.level.padded() {
+		b.Write(e.Label)
+		n := utf8.RuneCount(e.Label)
+		n += spaces(b, enc.level.pad(n))
+		if n > 0 {
+			b.Write(space)
+			col += n + 1
+		}
+	}
+	if e.Prefix != nil {
+		b.Write(e.Prefix)
+		b.Write(space)
+	}
+	if enc.multiline {
+		writeIndented(b, e.Message, col)
+	} else {
+		b.WriteString(e.Message)
+	}
+	end()
+
+	var multi []consolePair
+	var lines []string
+	var perLine = enc.fieldLines > 0 && len(ps) > enc.fieldLines
+	for _, p := range ps {
+		if enc.multiline {
+			var tmp bytes.Buffer
+			p.value(&tmp, kv.PlainAppender)
+			if s := tmp.String(); strings.Contains(s, "\n") {
+				multi, lines = append(multi, p), append(lines, s)
+				continue
+			}
+		}
+		if perLine {
+			b.Write(newline)
+			spaces(b, col)
+		} else {
+			b.Write(space)
+		}
+		enc.pair(b, p, style, func() { p.value(b, enc.append) })
+	}
+	for i, p := range multi {
+		b.Write(newline)
+		spaces(b, col)
+		enc.pair(b, p, style, func() {
+			for _, ln := range strings.Split(strings.TrimRight(lines[i], "\n"), "\n") {
+				b.Write(newline)
+				spaces(b, col+4)
+				b.WriteString(ln)
+			}
+		})
+	}
+	b.Write(newline)
+
+	if !ok {
+		_, err = w.Write(b.Bytes())
+	}
+	return err
+}
+
+// pair writes the styled key and value of p, the value is written by value
+func (enc *consoleEncoder) pair(b *bytes.Buffer, p consolePair, style func([]byte) func(), value func()) {
+	end := style(enc.style.key)
+	enc.append.Key(b, p.key)
+	end()
+	if p.isErr || p.key == "err" || p.key == "error" {
+		end = style(enc.style.err)
+	} else {
+		end = style(enc.style.value)
+	}
+	value()
+	end()
+}
+
+// spaces writes n spaces to b and returns n
+func spaces(b *bytes.Buffer, n int) int {
+	for i := 0; i < n; i++ {
+		b.WriteByte(' ')
+	}
+	return n
+}
+
+// writeIndented writes s to b with every line after the first indented by n spaces
+func writeIndented(b *bytes.Buffer, s string, n int) {
+	for i := strings.IndexByte(s, '\n'); i >= 0 && i < len(s)-1; i = strings.IndexByte(s, '\n') {
+		b.WriteString(s[:i+1])
+		spaces(b, n)
+		s = s[i+1:]
+	}
+	b.WriteString(s)
+}
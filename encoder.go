@@ -0,0 +1,184 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/njones/logger/kv"
+)
+
+// Entry is a single log line broken out into its parts, so that it can be
+// handed to an Encoder to write out in whatever shape is needed
+type Entry struct {
+	Time    time.Time // the time the line was logged
+	Stamp   []byte    // the formatted timestamp, nil when there is no timestamp
+	Level   logLevel  // zero for the plain Print(f,ln) functions
+	Label   []byte    // the level label for the current display (i.e. INFO:)
+	Caller  string    // the file:line when Llongfile or Lshortfile is set
+	Prefix  []byte    // the user prefix
+	Message string
+	Fields  []KeyVal // the structured K/V pairs sorted by key (or in order with WithOrderedFields)
+
+	// Color is the escape sequence for the line. It is nil when the
+	// Entry is being encoded for a writer that does not accept color.
+	Color []byte
+
+	typed   []field // the Event fields, only for the encoders that write them unboxed
+	labeled bool    // the Label is user-defined, so it is used in place of the level name
+	colored bool    // the Entry is being encoded for a writer that accepts color
+}
+
+// Encoder is the interface that writes an Entry to w as a single log line
+type Encoder interface {
+	Encode(w io.Writer, e *Entry) error
+}
+
+// EncoderFunc allows a plain function to be used as an Encoder
+type EncoderFunc func(io.Writer, *Entry) error
+
+// Encode satisfies the Encoder interface
+func (fn EncoderFunc) Encode(w io.Writer, e *Entry) error { return fn(w, e) }
+
+// typedEncoder is an Encoder that writes the typed Event fields itself,
+// any other Encoder gets those fields boxed and added to Fields
+type typedEncoder interface {
+	Encoder
+	encodesTyped()
+}
+
+// styledEncoder is an Encoder that styles the parts of a line, so a line
+// without a level color is still encoded apart for the color writers
+type styledEncoder interface {
+	Encoder
+	stylesLine()
+}
+
+// textEncoder is the default encoder, it writes the time, color, filename,
+// level, user prefix, message and then any K/V pairs separated by spaces
+type textEncoder struct {
+	marshal func(interface{}) ([]byte, error)
+	append  *kv.Appender // nil when marshal is not a kv func
+	ordered bool
+	layout  layout // nil for the DefaultLayout
+
+	buf bytes.Buffer // used when w is not already a buffer
+}
+
+func (*textEncoder) encodesTyped() {}
+
+// Encode satisfies the Encoder interface, the line is built up then written with a
+// single Write, unless w is a *bytes.Buffer, then the line is built up in w directly
+func (enc *textEncoder) Encode(w io.Writer, e *Entry) (err error) {
+	var typed = e.typed
+	var fields = e.Fields
+	if enc.append == nil && len(typed) > 0 {
+		fields, typed = appendKV(fields, typed), nil
+	}
+
+	var kv []byte
+	if len(fields) > 0 && enc.ordered {
+		if kv, err = enc.marshal(fieldList(fields)); err != nil {
+			return err
+		}
+	} else if len(fields) > 0 {
+		var m = mPool.Get().(map[string]interface{})
+		for _, field := range fields {
+			m[string(field.Key)] = field.Value
+		}
+		kv, err = enc.marshal(m)
+		for k := range m {
+			delete(m, k)
+		}
+		mPool.Put(m)
+		if err != nil {
+			return err // nothing has been written yet
+		}
+	}
+
+	var b, ok = w.(*bytes.Buffer)
+	if !ok {
+		b = &enc.buf
+		b.Reset()
+	}
+
+	if enc.layout != nil {
+		enc.encodeLayout(b, e, kv, typed)
+		if !ok {
+			_, err = w.Write(b.Bytes())
+		}
+		return err
+	}
+
+	if len(e.Stamp) > 0 {
+		b.Write(e.Stamp)
+		b.Write(space)
+	}
+	if len(e.Color) > 0 {
+		b.Write(e.Color)
+	}
+	if len(e.Caller) > 0 {
+		b.WriteString(e.Caller)
+		b.Write(space)
+	}
+	if e.Label != nil {
+		b.Write(e.Label)
+		b.Write(space)
+	}
+	if e.Prefix != nil {
+		b.Write(e.Prefix)
+		b.Write(space)
+	}
+	b.WriteString(e.Message)
+	if len(e.Color) > 0 {
+		b.Write(colorEnd)
+	}
+	if len(kv) > 0 || len(typed) > 0 {
+		b.Write(space)
+		enc.fields(b, kv, typed)
+	}
+	b.Write(newline)
+
+	if !ok {
+		_, err = w.Write(b.Bytes())
+	}
+	return err
+}
+
+// fields writes the marshaled K/V pairs followed by the typed Event fields
+func (enc *textEncoder) fields(b *bytes.Buffer, kv []byte, typed []field) {
+	b.Write(kv)
+	for i, f := range typed {
+		if i > 0 || len(kv) > 0 {
+			b.WriteString(enc.append.Sep())
+		}
+		enc.append.Key(b, f.key)
+		f.appendText(b, enc.append)
+	}
+}
+
+var (
+	space    = []byte{' '}
+	newline  = []byte{'\n'}
+	colorEnd = []byte{0x1b, '[', '0', 'm'}
+)
+
+// fieldList passes the K/V pairs to a marshaler while keeping their order, it works
+// with kv.Marshal (as a kv.Ordered) and json.Marshal (as a json.Marshaler)
+type fieldList []KeyVal
+
+// Len satisfies the kv.Ordered interface
+func (fl fieldList) Len() int { return len(fl) }
+
+// KV satisfies the kv.Ordered interface
+func (fl fieldList) KV(i int) (string, interface{}) { return string(fl[i].Key), fl[i].Value }
+
+// MarshalJSON writes the K/V pairs as a JSON object in order
+func (fl fieldList) MarshalJSON() ([]byte, error) {
+	var b = []byte{'{'}
+	for _, field := range fl {
+		b = appendJSONKey(b, string(field.Key))
+		b = appendJSONValue(b, field.Value)
+	}
+	return append(b, '}'), nil
+}
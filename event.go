@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/njones/logger/kv"
+)
+
+var ePool = sync.Pool{
+	New: func() interface{} {
+		return &Event{fields: make([]field, 0, 16)}
+	},
+}
+
+// Event is a single log line that is built up with typed fields and then
+// written with Msg, Msgf or Send. i.e.
+//
+//	log.Event(Info).Str("user", u).Int("n", 3).Err(err).Msg("done")
+//
+// The typed fields are kept as they are (not boxed in an interface{}) and are
+// written directly by the built-in encoders, so an Event that only uses typed
+// fields does not allocate. The fields are written after any Field(s) values in
+// the order they were added. An Event comes from a pool and must not be used
+// after it is sent. A nil Event (from a level that is not enabled) does nothing.
+type Event struct {
+	b      *baseLogger
+	level  logLevel
+	fields []field
+	v      []interface{} // the Any values, these are boxed already
+}
+
+type fieldKind uint8
+
+const (
+	fString fieldKind = iota
+	fInt
+	fUint
+	fFloat
+	fBool
+	fErr
+	fDur
+	fTime
+)
+
+// field is a single typed K/V pair, only the part for the kind is set
+type field struct {
+	key  string
+	kind fieldKind
+	str  string
+	num  uint64 // the bits for ints, uints, floats, bools and durations
+	err  error
+	time time.Time
+}
+
+// Event returns a new Event for the level, or nil when the level is not enabled. This is
+// not named for the level (i.e. Info()) because those are the existing log methods.
+func (b *baseLogger) Event(level logLevel) *Event {
+	if !b.Enabled(level) {
+		return nil
+	}
+	var ev = ePool.Get().(*Event)
+	ev.b, ev.level = b, level
+	return ev
+}
+
+func (ev *Event) add(f field) *Event {
+	if ev != nil {
+		ev.fields = append(ev.fields, f)
+	}
+	return ev
+}
+
+// Str adds a string field
+func (ev *Event) Str(key, value string) *Event {
+	return ev.add(field{key: key, kind: fString, str: value})
+}
+
+// Int adds an int field
+func (ev *Event) Int(key string, value int) *Event {
+	return ev.add(field{key: key, kind: fInt, num: uint64(value)})
+}
+
+// Int64 adds an int64 field
+func (ev *Event) Int64(key string, value int64) *Event {
+	return ev.add(field{key: key, kind: fInt, num: uint64(value)})
+}
+
+// Uint64 adds a uint64 field
+func (ev *Event) Uint64(key string, value uint64) *Event {
+	return ev.add(field{key: key, kind: fUint, num: value})
+}
+
+// Float64 adds a float64 field
+func (ev *Event) Float64(key string, value float64) *Event {
+	return ev.add(field{key: key, kind: fFloat, num: math.Float64bits(value)})
+}
+
+// Bool adds a bool field
+func (ev *Event) Bool(key string, value bool) *Event {
+	var n uint64
+	if value {
+		n = 1
+	}
+	return ev.add(field{key: key, kind: fBool, num: n})
+}
+
+// Err adds the error using the key "err", a nil error is written as <nil> (or null in JSON)
+func (ev *Event) Err(err error) *Event {
+	return ev.add(field{key: "err", kind: fErr, err: err})
+}
+
+// Dur adds a time.Duration field, it is written using String() (i.e. 1.5s)
+func (ev *Event) Dur(key string, value time.Duration) *Event {
+	return ev.add(field{key: key, kind: fDur, num: uint64(value)})
+}
+
+// Time adds a time.Time field, it is written as RFC3339 with nanoseconds
+func (ev *Event) Time(key string, value time.Time) *Event {
+	return ev.add(field{key: key, kind: fTime, time: value})
+}
+
+// Any adds a value of any type, it is written the same as a KV value (so
+// it is boxed and written with the Field(s) values)
+func (ev *Event) Any(key string, value interface{}) *Event {
+	if ev != nil {
+		ev.v = append(ev.v, KeyVal{K(key), value})
+	}
+	return ev
+}
+
+// Msg writes the Event with msg as the message
+func (ev *Event) Msg(msg string) {
+	if ev != nil {
+		ev.b.event(ev, bMsg, msg, ev.v)
+		ev.done()
+	}
+}
+
+// Msgf writes the Event with the formatted message
+func (ev *Event) Msgf(format string, v ...interface{}) {
+	if ev != nil {
+		ev.b.event(ev, bPrintf, format, append(ev.v, v...))
+		ev.done()
+	}
+}
+
+// Send writes the Event with an empty message
+func (ev *Event) Send() {
+	if ev != nil {
+		ev.b.event(ev, bMsg, "", ev.v)
+		ev.done()
+	}
+}
+
+// done puts the Event back in the pool, then exits or panics for those levels
+func (ev *Event) done() {
+	var b, level = ev.b, ev.level
+
+	for i := range ev.fields {
+		ev.fields[i] = field{} // don't hold on to the strings and errors
+	}
+	for i := range ev.v {
+		ev.v[i] = nil
+	}
+	ev.b, ev.level, ev.fields, ev.v = nil, 0, ev.fields[:0], ev.v[:0]
+	ePool.Put(ev)
+
+	b.exited(level)
+}
+
+// event writes the Event using the same line setup as print, but without
+// passing the level values as settings, which would box them
+func (b *baseLogger) event(ev *Event, prnt printKind, format string, v []interface{}) error {
+	var ln = b.newLine(prnt)
+	defer ln.free()
+
+	ln.level = ev.level
+	ln.prefixLevel = b.levelize(ev.level)
+	ln.format = format
+	ln.typed = ev.fields
+	b.colorize(ev.level).set(ln)
+	if ev.level == Panic {
+		b.exit.buf = new(bytes.Buffer)
+		writeize{b.exit.buf}.set(ln)
+	}
+
+	ln.v, ln.kv = b.filter(v, ln.kv)
+
+	b.sync.ln.Lock()
+	defer b.sync.ln.Unlock()
+
+	return ln.write()
+}
+
+// appendKV adds the typed fields to kvs as boxed values, for encoders
+// that can not write the typed fields themselves
+func appendKV(kvs []KeyVal, fields []field) []KeyVal {
+	for _, f := range fields {
+		var v V
+		switch f.kind {
+		case fString:
+			v = f.str
+		case fInt:
+			v = int64(f.num)
+		case fUint:
+			v = f.num
+		case fFloat:
+			v = math.Float64frombits(f.num)
+		case fBool:
+			v = f.num == 1
+		case fErr:
+			v = f.err
+		case fDur:
+			v = time.Duration(f.num)
+		case fTime:
+			v = f.time
+		}
+		kvs = append(kvs, KeyVal{K(f.key), v})
+	}
+	return kvs
+}
+
+// appendText writes the field value in the format of the Appender
+func (f field) appendText(buf *bytes.Buffer, a *kv.Appender) {
+	var tmp [64]byte
+	switch f.kind {
+	case fString:
+		a.String(buf, f.str)
+	case fInt:
+		buf.Write(strconv.AppendInt(tmp[:0], int64(f.num), 10))
+	case fUint:
+		buf.Write(strconv.AppendUint(tmp[:0], f.num, 10))
+	case fFloat:
+		buf.Write(strconv.AppendFloat(tmp[:0], math.Float64frombits(f.num), 'g', -1, 64))
+	case fBool:
+		buf.Write(strconv.AppendBool(tmp[:0], f.num == 1))
+	case fErr:
+		if f.err == nil {
+			buf.WriteString("<nil>")
+			return
+		}
+		a.String(buf, f.err.Error())
+	case fDur:
+		buf.WriteString(time.Duration(f.num).String())
+	case fTime:
+		buf.Write(f.time.AppendFormat(tmp[:0], time.RFC3339Nano))
+	}
+}
+
+// appendJSON appends the field value as JSON
+func (f field) appendJSON(b []byte) []byte {
+	switch f.kind {
+	case fString:
+		return appendJSONString(b, f.str)
+	case fInt:
+		return strconv.AppendInt(b, int64(f.num), 10)
+	case fUint:
+		return strconv.AppendUint(b, f.num, 10)
+	case fFloat:
+		if n := math.Float64frombits(f.num); !math.IsNaN(n) && !math.IsInf(n, 0) {
+			return strconv.AppendFloat(b, n, 'g', -1, 64)
+		}
+		return appendJSONString(b, strconv.FormatFloat(math.Float64frombits(f.num), 'g', -1, 64))
+	case fBool:
+		return strconv.AppendBool(b, f.num == 1)
+	case fErr:
+		if f.err == nil {
+			return append(b, "null"...)
+		}
+		return appendJSONString(b, f.err.Error())
+	case fDur:
+		return appendJSONString(b, time.Duration(f.num).String())
+	case fTime:
+		b = append(b, '"')
+		b = f.time.AppendFormat(b, time.RFC3339Nano)
+		return append(b, '"')
+	}
+	return append(b, "null"...)
+}
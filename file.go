@@ -0,0 +1,423 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fwOpt defines a typed functional option interface for a FileWriter
+type fwOpt interface {
+	setOption(*fileWriter)
+}
+
+// FileMaxSize rotates the file before a write would make it larger than the number of bytes
+type FileMaxSize int64
+
+// FileMaxBackups is the most rotated files that are kept, the oldest are removed first
+type FileMaxBackups int
+
+// FileMaxAge removes rotated files that are older than the duration
+type FileMaxAge time.Duration
+
+// FileCompress gzips the rotated files
+type FileCompress bool
+
+// FileSymlink is a symlink that is kept pointing at the current file, so a `tail -F`
+// of the symlink follows the file across each rotation
+type FileSymlink string
+
+// setOption satisfies the functional option interface for a FileWriter
+func (n FileMaxSize) setOption(fw *fileWriter) { fw.maxSize = int64(n) }
+
+// setOption satisfies the functional option interface for a FileWriter
+func (n FileMaxBackups) setOption(fw *fileWriter) { fw.maxBackups = int(n) }
+
+// setOption satisfies the functional option interface for a FileWriter
+func (d FileMaxAge) setOption(fw *fileWriter) { fw.maxAge = time.Duration(d) }
+
+// setOption satisfies the functional option interface for a FileWriter
+func (c FileCompress) setOption(fw *fileWriter) { fw.compress = bool(c) }
+
+// setOption satisfies the functional option interface for a FileWriter
+func (l FileSymlink) setOption(fw *fileWriter) { fw.link = string(l) }
+
+// backupStamp is the time added to a rotated file name, i.e. app-2020-01-02T03-04-05.000.log
+const backupStamp = "2006-01-02T15-04-05.000"
+
+// FileWriter is a helper function that will log writes to the file at path, the file (and
+// any directories) are created when needed. With FileMaxSize the file is rotated by renaming it
+// with the time added to the name (i.e. app.log is renamed to app-2020-01-02T03-04-05.000.log).
+//
+// The path can be a template with time layouts in braces, i.e. logs/app-{2006-01-02}.log, then
+// a new file is started each time the formatted path changes (daily for this example, hourly for
+// {2006-01-02T15}). The files from the past periods are treated the same as the rotated files.
+//
+// After a rotation the rotated files in the directory of the current file are compressed and
+// removed in the background using the other options, the age of a file comes from the time in
+// its name. The writer is kept with the logger's closers, and any errors from a cleanup (or
+// from updating the FileSymlink) are written to stderr.
+func FileWriter(path string, opts ...fwOpt) io.Writer {
+	fw := &fileWriter{path: path, now: time.Now}
+	fw.template()
+	for _, opt := range opts {
+		opt.setOption(fw)
+	}
+	return fw
+}
+
+// fileWriter the underling struct that writes to and rotates the file
+type fileWriter struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+	link       string
+
+	stamp   string         // the path with the layouts from `convertStamp`, empty when path isn't a template
+	layouts []string       // the time layouts in the file name
+	match   *regexp.Regexp // matches the rotated file names, with the layouts, the backup time then the backup count as groups
+	name    []byte         // the formatted path template
+
+	file    *os.File
+	current string // the path of the open file
+	size    int64
+	now     func() time.Time
+
+	m    sync.Mutex     // keeps the writes, rotates and close's in sync
+	mill sync.WaitGroup // the background compress and remove after a rotate
+	err  error
+}
+
+// template breaks up the path into the stamp used to format the path, and the pattern
+// that matches the files that have been rotated
+func (fw *fileWriter) template() {
+	var dir, base = filepath.Split(fw.path)
+	var ext = filepath.Ext(base)
+
+	var stamp strings.Builder
+	holes(dir, func(text string) { stamp.WriteString(text) }, func(layout string) {
+		stamp.WriteString(convertStamp(layout))
+	})
+
+	var re strings.Builder
+	re.WriteString("^")
+	holes(strings.TrimSuffix(base, ext), func(text string) {
+		stamp.WriteString(text)
+		re.WriteString(regexp.QuoteMeta(text))
+	}, func(layout string) {
+		stamp.WriteString(convertStamp(layout))
+		re.WriteString("(.+?)")
+		fw.layouts = append(fw.layouts, layout)
+	})
+	stamp.WriteString(ext)
+	re.WriteString(`(?:-(\d{4}-\d\d-\d\dT\d\d-\d\d-\d\d\.\d{3})(?:-(\d+))?)?` + regexp.QuoteMeta(ext) + `(?:\.gz)?$`)
+
+	if stamp.String() != fw.path {
+		fw.stamp = stamp.String()
+	}
+	fw.match = regexp.MustCompile(re.String())
+}
+
+// Write passes writes to the file, starting a new file first when the path template has
+// changed or rotating the file when it would be too large
+func (fw *fileWriter) Write(p []byte) (n int, err error) {
+	fw.m.Lock()
+	defer fw.m.Unlock()
+
+	var now time.Time // only needed for a path template
+	if fw.stamp != "" {
+		now = fw.now()
+		fw.name = appendStamp(fw.name[:0], fw.stamp, now)
+		if fw.file != nil && string(fw.name) != fw.current {
+			if fw.err = fw.file.Close(); fw.err != nil {
+				return 0, fw.err
+			}
+			fw.file = nil
+			defer fw.cleanup(now) // after the new file is open, so the old file is cleaned up
+		}
+	}
+	if fw.file == nil {
+		if fw.err = fw.open(); fw.err != nil {
+			return 0, fw.err
+		}
+	}
+	if fw.maxSize > 0 && fw.size > 0 && fw.size+int64(len(p)) > fw.maxSize {
+		if fw.err = fw.rotate(); fw.err != nil {
+			return 0, fw.err
+		}
+	}
+
+	n, err = fw.file.Write(p)
+	fw.size += int64(n)
+	return n, err
+}
+
+// open opens the current file for appending, creating it and its directories if need be
+func (fw *fileWriter) open() error {
+	var path = fw.path
+	if fw.stamp != "" {
+		path = string(fw.name)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	fw.file, fw.current, fw.size = f, path, fi.Size()
+
+	if fw.link != "" {
+		if err := symlink(path, fw.link); err != nil {
+			stderr(err)
+		}
+	}
+	return nil
+}
+
+// symlink points link at path, the new link is renamed over the old one so
+// there is always a link in place
+func symlink(path, link string) error {
+	var target = path
+	if rel, err := filepath.Rel(filepath.Dir(link), path); err == nil {
+		target = rel
+	}
+	var tmp = link + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, link)
+}
+
+// rotate renames the current file to a backup and opens a new file in its place
+func (fw *fileWriter) rotate() error {
+	if err := fw.file.Close(); err != nil {
+		return err
+	}
+	fw.file = nil
+
+	var now = fw.now()
+	if err := os.Rename(fw.current, backupName(fw.current, now)); err != nil {
+		return err
+	}
+	if err := fw.open(); err != nil {
+		return err
+	}
+
+	fw.cleanup(now)
+	return nil
+}
+
+// cleanup starts the background compress and remove of the rotated files
+func (fw *fileWriter) cleanup(now time.Time) {
+	if fw.maxBackups == 0 && fw.maxAge == 0 && !fw.compress {
+		return
+	}
+
+	var current = fw.current
+	fw.mill.Add(1)
+	go func() {
+		defer fw.mill.Done()
+		fw.prune(current, now)
+	}()
+}
+
+// backupName adds the time to the file name before the extension, when there is already a
+// backup with that name (i.e. two rotations in the same millisecond) a count is added after
+// the time, so app-2020-01-02T03-04-05.000.log is followed by app-2020-01-02T03-04-05.000-1.log
+func backupName(path string, t time.Time) string {
+	var ext = filepath.Ext(path)
+	var base = strings.TrimSuffix(path, ext) + "-" + t.Format(backupStamp)
+	var name = base + ext
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = base + "-" + strconv.Itoa(i) + ext
+	}
+	return name
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return !os.IsNotExist(err)
+}
+
+// backup is a rotated file and the time (and count) in its name
+type backup struct {
+	path  string
+	time  time.Time
+	count int
+}
+
+// backups returns the rotated files in the same directory as current, the newest first
+func (fw *fileWriter) backups(current string) ([]backup, error) {
+	var dir = filepath.Dir(current)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var bs []backup
+	for _, fi := range files {
+		var path = filepath.Join(dir, fi.Name())
+		var m = fw.match.FindStringSubmatch(fi.Name())
+		if m == nil || fi.IsDir() || path == current {
+			continue
+		}
+
+		var layout, value = backupStamp, m[len(m)-2]
+		if value == "" {
+			layout, value = strings.Join(fw.layouts, " "), strings.Join(m[1:len(m)-2], " ")
+		}
+		if value == "" {
+			continue // there's no stamp (i.e. app.log.gz), so it's not a rotated file
+		}
+		var count, _ = strconv.Atoi(m[len(m)-1])
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			bs = append(bs, backup{path: path, time: t, count: count})
+		}
+	}
+	sort.Slice(bs, func(i, j int) bool {
+		if bs[i].time.Equal(bs[j].time) {
+			return bs[i].count > bs[j].count
+		}
+		return bs[i].time.After(bs[j].time)
+	})
+	return bs, nil
+}
+
+// prune removes the rotated files past FileMaxBackups or FileMaxAge, then compresses the rest.
+// Any files newer than now are left for a later prune, as they may be in use. Only one prune
+// runs at a time, so a file isn't compressed twice.
+func (fw *fileWriter) prune(current string, now time.Time) {
+	cleanupMu.Lock()
+	defer cleanupMu.Unlock()
+
+	bs, err := fw.backups(current)
+	if err != nil {
+		stderr(err)
+		return
+	}
+
+	var i int
+	var cutoff = now.Add(-fw.maxAge)
+	for _, b := range bs {
+		if b.time.After(now) {
+			continue
+		}
+		i++
+		if (fw.maxBackups > 0 && i > fw.maxBackups) || (fw.maxAge > 0 && b.time.Before(cutoff)) {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				stderr(err)
+			}
+			continue
+		}
+		if fw.compress && !strings.HasSuffix(b.path, ".gz") {
+			if err := gzipFile(b.path); err != nil {
+				stderr(err)
+			}
+		}
+	}
+}
+
+var cleanupMu sync.Mutex
+
+// gzipFile compresses the file to file.gz then removes the file
+func gzipFile(path string) (err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(path + ".gz")
+		}
+	}()
+
+	zw := gzip.NewWriter(out)
+	if _, err = io.Copy(zw, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
+
+// stderr writes the error from a background cleanup, because there is no write to return it from
+func stderr(err error) { os.Stderr.WriteString("logger: " + err.Error() + "\n") }
+
+// Close closes the file and waits for any background cleanup to finish, the file is
+// opened again on the next write
+func (fw *fileWriter) Close() error {
+	fw.m.Lock()
+	defer fw.m.Unlock()
+
+	fw.mill.Wait()
+	fw.err = nil // an earlier error is not from this close
+	if fw.file != nil {
+		fw.err = fw.file.Close()
+	}
+	fw.file = nil
+	return fw.err
+}
+
+// Reopen closes the file and opens the file at the path again, so that writes go to a new file
+// after the file was moved by an external logrotate. The file is opened even if an earlier open
+// or the close failed, so a Reopen can recover once the path is usable again.
+func (fw *fileWriter) Reopen() error {
+	fw.m.Lock()
+	defer fw.m.Unlock()
+
+	var err error
+	if fw.file != nil {
+		err = fw.file.Close()
+		fw.file = nil
+	}
+	if fw.err = fw.open(); fw.err == nil {
+		fw.err = err
+	}
+	return fw.err
+}
+
+// Sync commits the file to disk
+func (fw *fileWriter) Sync() error {
+	fw.m.Lock()
+	defer fw.m.Unlock()
+
+	if fw.file == nil {
+		return nil
+	}
+	return fw.file.Sync()
+}
+
+func (fw *fileWriter) Err() error { return fw.err }
+
+func (fw *fileWriter) NoColor() {}
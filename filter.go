@@ -53,3 +53,6 @@ type filterWriter struct {
 func (fw filterWriter) Write(p []byte) (n int, err error) {
 	return fw.w.Write(p)
 }
+
+// unwrap returns the writer that is written to
+func (fw filterWriter) unwrap() io.Writer { return fw.w }
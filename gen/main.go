@@ -78,12 +78,14 @@ func main() {
 		Timestamp       time.Time
 		TimestampMap    map[byte]string
 		Levels          []데이터
+		LastLevel       string
 	}{
 		LevelDisplay:    []string{"default", "box", "short", "short.box"},
 		DuplicateFields: fields,
 		Timestamp:       time.Now(),
 		TimestampMap:    timestampMap,
 		Levels:          levelsData,
+		LastLevel:       levelsData[len(levelsData)-1].FuncName,
 	})
 
 	type onErrData struct {
@@ -164,13 +166,17 @@ func FindTypes(n ast.Node) ast.Visitor {
 
 type 데이터 struct {
 	// for creating the maps
-	Levels map[string]string
-	Color  string
+	Levels   map[string]string
+	Color    string
+	Severity string
+	Syslog   string
 
 	// for creating the functions
 	FuncName  string
 	LevelName string // in the case of Print (the level is different)
+	Name      string // the lowercase name returned from String()
 
+	Leveled  string
 	Writeize string
 	Levelize string
 	Colorize string
@@ -181,6 +187,7 @@ type 데이터 struct {
 	AsPrint   bool
 	AsPrintf  bool
 	AsPrintln bool
+	AsPrintt  bool
 
 	AsPrintTest   bool
 	AsPrintfTest  bool
@@ -245,12 +252,14 @@ func 𓁹𓁹(name, comment string) {
 	var ᄀ 데이터
 
 	ᄀ.HasOnErr = true
-	ᄀ.AsPrint, ᄀ.AsPrintf, ᄀ.AsPrintln = true, true, true
+	ᄀ.AsPrint, ᄀ.AsPrintf, ᄀ.AsPrintln, ᄀ.AsPrintt = true, true, true, true
 	ᄀ.AsPrintTest, ᄀ.AsPrintfTest, ᄀ.AsPrintlnTest = true, true, true
 	ᄀ.FuncName = strings.Title(name)
 	ᄀ.LevelName = ᄀ.FuncName
-	ᄀ.Levelize = fmt.Sprintf(", %s.levelize(b.display)", name)
-	ᄀ.Colorize = fmt.Sprintf(", %s.colorize()", name)
+	ᄀ.Name = strings.ToLower(name)
+	ᄀ.Leveled = fmt.Sprintf(", %s", name)
+	ᄀ.Levelize = fmt.Sprintf(", b.levelize(%s)", name)
+	ᄀ.Colorize = fmt.Sprintf(", b.colorize(%s)", name)
 
 	ᄀ.Levels = map[string]string{
 		"default": strings.ToUpper(name) + ":",
@@ -287,8 +296,12 @@ func 𓁹𓁹(name, comment string) {
 					ᄀ.Color = fmt.Sprintf(`\x1b[%dm`, i+30)
 				}
 			}
+		case "severity":
+			ᄀ.Severity = val
+		case "syslog":
+			ᄀ.Syslog = val
 		case "fn":
-			ᄀ.AsPrint, ᄀ.AsPrintf, ᄀ.AsPrintln = false, false, false
+			ᄀ.AsPrint, ᄀ.AsPrintf, ᄀ.AsPrintln, ᄀ.AsPrintt = false, false, false, false
 			types := strings.Split(strings.ToLower(val), ",")
 			for _, t := range types {
 				switch t {
@@ -298,6 +311,8 @@ func 𓁹𓁹(name, comment string) {
 					ᄀ.AsPrintf = true
 				case "ln":
 					ᄀ.AsPrintln = true
+				case "t":
+					ᄀ.AsPrintt = true
 				}
 			}
 		}
@@ -310,6 +325,7 @@ func 𓁹𓁹(name, comment string) {
 	// a request from the "Keep Print Plain campaign"...
 	if ᄀ.FuncName == "Print" {
 		ᄀ.Levels = map[string]string{}
+		ᄀ.Leveled = ""
 		ᄀ.Levelize = ""
 		ᄀ.Colorize = ""
 		ᄀ.Color = ""
@@ -366,32 +382,82 @@ var tsRuneMap = map[rune]string{
 	{{ end -}}
 }
 
-var llMap = map[int]map[logLevel]string{
+var llMap = map[int]map[logLevel]levelize{
 	{{- $out := . -}}
 	{{ range $idx, $val := .LevelDisplay }}
-	{{ printf "%d" $idx }}: map[logLevel]string{
+	{{ printf "%d" $idx }}: map[logLevel]levelize{
 		{{- range $index, $value := $out.Levels -}}
-		{{ with (index $value.Levels $val) }}{{ $value.FuncName }}: "{{ . }}",{{ end }}
+		{{ with (index $value.Levels $val) }}{{ $value.FuncName }}: levelize("{{ . }}"),{{ end }}
 		{{ end -}}
 	},
 	{{- end }}
 }
 
+var llNames = map[logLevel]string{
+	{{- range $index, $value := $out.Levels -}}
+	{{- if (ne $value.FuncName "Print") -}}
+	{{- $value.FuncName -}}: "{{ $value.Name }}",
+	{{- end }}
+	{{ end -}}
+}
+
+var llColors = map[logLevel]colorize{
+	{{- range $index, $value := $out.Levels -}}
+	{{- if (and (gt $index 0) (ne $value.Color "" )) -}}
+	{{- $value.FuncName -}}: colorize("{{ $value.Color }}"),
+	{{- end }}
+	{{ end -}}
+}
+
+var llSeverity = map[logLevel]int{
+	{{- range $index, $value := $out.Levels -}}
+	{{- if (and (ne $value.FuncName "Print") (ne $value.Severity "")) -}}
+	{{- $value.FuncName -}}: {{ $value.Severity }},
+	{{- end }}
+	{{ end -}}
+}
+
+var llSyslog = map[logLevel]int{
+	{{- range $index, $value := $out.Levels -}}
+	{{- if (and (ne $value.FuncName "Print") (ne $value.Syslog "")) -}}
+	{{- $value.FuncName -}}: {{ $value.Syslog }},
+	{{- end }}
+	{{ end -}}
+}
+
+// llNext is the first level bit that is free for RegisterLevel to use
+const llNext = {{ .LastLevel }} << 1
+
 func (ll logLevel) flag() int { return int(ll) }
 
+// severity returns the order of the level, it is higher the more severe the level
+func (ll logLevel) severity() int {
+	if s, ok := llSeverity[ll]; ok {
+		return s
+	}
+	return ll.registered().severity
+}
+
+// String returns the lowercase name of the log level
+func (ll logLevel) String() string {
+	if s, ok := llNames[ll]; ok {
+		return s
+	}
+	return ll.registered().name
+}
+
 func (ll logLevel) levelize(display int) levelize {
-	return levelize(llMap[display][ll])
+	if l, ok := llMap[display][ll]; ok {
+		return l
+	}
+	return ll.registered().levelize(display)
 }
 
 func (ll logLevel) colorize() colorize {
-	m := map[logLevel]string{
-		{{- range $index, $value := $out.Levels -}}
-		{{- if (and (gt $index 0) (ne $value.Color "" )) -}}
-		{{- $value.FuncName -}}: "{{ $value.Color }}",
-		{{- end }}
-		{{ end -}}
+	if c, ok := llColors[ll]; ok {
+		return c
 	}
-	return colorize(m[ll])
+	return ll.registered().color
 }
 
 // Helper Functions
@@ -409,27 +475,36 @@ func duplicate(b *baseLogger) *baseLogger {
 {{ range $idx, $value := .Levels }}
 {{ if $value.AsPrint }}
 func (b *baseLogger) {{ $value.FuncName }}(v ...interface{}) {
-	if !hasFlag(b.suppress, {{ $value.LevelName }}.flag()) {
+	if b.Enabled({{ $value.LevelName }}) {
 		{{- template "preHook" . -}}
-		b.print(bPrint, v{{ $value.Writeize }}{{ $value.Levelize }}{{ $value.Colorize }}{{ $value.Timeize }});
+		b.print(bPrint, v{{ $value.Writeize }}{{ $value.Leveled }}{{ $value.Levelize }}{{ $value.Colorize }}{{ $value.Timeize }});
 		{{- template "postHook" . -}}
 	}
 }
 {{ end }}
 {{ if $value.AsPrintf }}
 func (b *baseLogger) {{ $value.FuncName }}f(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, {{ $value.LevelName }}.flag()) {
+	if b.Enabled({{ $value.LevelName }}) {
 		{{- template "preHook" . -}}
-		b.print(bPrintf, v, formatize(f){{ $value.Writeize }}{{ $value.Levelize }}{{ $value.Colorize }}{{ $value.Timeize }});
+		b.print(bPrintf, v, formatize(f){{ $value.Writeize }}{{ $value.Leveled }}{{ $value.Levelize }}{{ $value.Colorize }}{{ $value.Timeize }});
 		{{- template "postHook" . -}}
 	}
 }
 {{ end }}
 {{ if $value.AsPrintln }}
 func (b *baseLogger) {{ $value.FuncName }}ln(v ...interface{}) {
-	if !hasFlag(b.suppress, {{ $value.LevelName }}.flag()) {
+	if b.Enabled({{ $value.LevelName }}) {
+		{{- template "preHook" . -}}
+		b.print(bPrintln, v{{ $value.Writeize }}{{ $value.Leveled }}{{ $value.Levelize }}{{ $value.Colorize }}{{ $value.Timeize }});
+		{{- template "postHook" . -}}
+	}
+}
+{{ end }}
+{{ if $value.AsPrintt }}
+func (b *baseLogger) {{ $value.FuncName }}t(t string, v ...interface{}) {
+	if b.Enabled({{ $value.LevelName }}) {
 		{{- template "preHook" . -}}
-		b.print(bPrintln, v{{ $value.Writeize }}{{ $value.Levelize }}{{ $value.Colorize }}{{ $value.Timeize }});
+		b.print(bPrintt, v, formatize(t){{ $value.Writeize }}{{ $value.Leveled }}{{ $value.Levelize }}{{ $value.Colorize }}{{ $value.Timeize }});
 		{{- template "postHook" . -}}
 	}
 }
@@ -466,6 +541,15 @@ func (e *onErrLogger) {{$value.FuncName}}ln(v ...interface{}) (rtn Return) {
 	return rtn
 }
 {{ end }}
+{{ if and $value.AsPrintt $value.HasOnErr }}
+func (e *onErrLogger) {{$value.FuncName}}t(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.{{$value.FuncName}}t(t, v...)
+	}
+	return rtn
+}
+{{ end }}
 {{ end }}
 `))
 
@@ -1,7 +1,10 @@
 package logger
 
 import (
+	"io"
+	"os"
 	"strings"
+	"time"
 )
 
 func hasFlag(has int, flags ...int) bool {
@@ -13,6 +16,53 @@ func hasFlag(has int, flags ...int) bool {
 	return false
 }
 
+// colorable returns true when the escape codes for color should be written to w. A
+// NoColorWriter (or a writer that wraps one) never gets color, then a non-empty NO_COLOR
+// turns color off and a non-empty FORCE_COLOR turns it on (unless it's 0 or false) for
+// every other writer. Otherwise only a terminal gets color, that is a *os.File (or a writer
+// that wraps one, i.e. an AsyncWriter) that is a tty, and not a file, pipe or buffer.
+func colorable(w io.Writer) bool {
+	for {
+		if _, ok := w.(NoColorWriter); ok {
+			return false
+		}
+		u, ok := w.(unwrapper)
+		if !ok {
+			break
+		}
+		w = u.unwrap()
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	switch force := os.Getenv("FORCE_COLOR"); strings.ToLower(force) {
+	case "":
+	case "0", "false":
+		return false
+	default:
+		return true
+	}
+	if f, ok := w.(*os.File); ok {
+		return isTerminal(f)
+	}
+	return false
+}
+
+// isTerminal returns true if f is a character device (i.e. a tty)
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// closerFunc allows a plain function to be used as an io.Closer
+type closerFunc func() error
+
+// Close satisfies the io.Closer interface
+func (fn closerFunc) Close() error { return fn() }
+
 func KV(k K, v V) KeyVal {
 	return KeyVal{k, v}
 }
@@ -26,6 +76,29 @@ func convertStamp(format string) string {
 	return format
 }
 
+// appendStamp appends the time formatted by a stamp from `convertStamp` to dst
+func appendStamp(dst []byte, stamp string, now time.Time) []byte {
+	var (
+		r    rune
+		n, i int
+	)
+
+	for i, r = range stamp {
+		if tsFormat, ok := tsRuneMap[r]; ok {
+			if i > n {
+				dst = append(dst, stamp[n:i]...)
+			}
+			dst = now.AppendFormat(dst, tsFormat)
+			n = i + 1
+			continue
+		}
+	}
+	if i >= n {
+		dst = append(dst, stamp[n:]...)
+	}
+	return dst
+}
+
 // tsMap is the string lookup for maping timestamps using the `convertStamp` function
 // NOTE: the string needs to be storted from longest string to shortest, so that the
 // lookup works as expected
@@ -0,0 +1,341 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/njones/logger/kv"
+)
+
+var jPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// JSONEncoder returns an Encoder that writes each log line as a single JSON object
+// with the keys: time, level, msg, caller, prefix and then all of the K/V fields.
+// The time is in RFC3339 (with nanoseconds), any empty time, level, caller or prefix
+// values are left out of the object. A K/V field that uses one of those keys is
+// written as fields.<key> (i.e. fields.msg), so it doesn't replace the message. Each key
+// is only written once, with the last value for it (i.e. from an Event field).
+func JSONEncoder() Encoder { return jsonEncoder{} }
+
+// jsonEncoder writes one JSON object per line
+type jsonEncoder struct{}
+
+func (jsonEncoder) encodesTyped() {}
+
+// Encode satisfies the Encoder interface
+func (jsonEncoder) Encode(w io.Writer, e *Entry) (err error) {
+	var bp = jPool.Get().(*[]byte)
+	var b = (*bp)[:0]
+	defer func() { *bp = b; jPool.Put(bp) }()
+
+	b = append(b, '{')
+	if len(e.Stamp) > 0 {
+		b = appendJSONKey(b, "time")
+		b = append(b, '"')
+		b = e.Time.AppendFormat(b, time.RFC3339Nano)
+		b = append(b, '"')
+	}
+	var level = e.Level.String()
+	if e.labeled {
+		level = string(bytes.TrimSpace(e.Label))
+	}
+	if level != "" {
+		b = appendJSONKey(b, "level")
+		b = appendJSONString(b, level)
+	}
+	b = appendJSONKey(b, "msg")
+	b = appendJSONString(b, e.Message)
+	if len(e.Caller) > 0 {
+		b = appendJSONKey(b, "caller")
+		b = appendJSONString(b, e.Caller)
+	}
+	if len(e.Prefix) > 0 {
+		b = appendJSONKey(b, "prefix")
+		b = appendJSONString(b, string(e.Prefix))
+	}
+	for _, field := range e.Fields {
+		if typedKey(e.typed, string(field.Key)) {
+			continue // the Event field replaces it
+		}
+		b = appendJSONKey(b, jsonField(string(field.Key)))
+		b = appendJSONValue(b, field.Value)
+	}
+	for i, f := range e.typed {
+		if typedKey(e.typed[i+1:], f.key) {
+			continue // a later Event field replaces it
+		}
+		b = appendJSONKey(b, jsonField(f.key))
+		b = f.appendJSON(b)
+	}
+	b = append(b, '}', '\n')
+
+	_, err = w.Write(b)
+	return err
+}
+
+// typedKey returns true when one of the typed fields has the key, so that a key is only
+// written once with its last value (the same as the text encoder)
+func typedKey(typed []field, key string) bool {
+	for _, f := range typed {
+		if f.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonField returns the key for a K/V field, which is prefixed when it's
+// one of the keys the encoder writes itself
+func jsonField(key string) string {
+	switch key {
+	case "time", "level", "msg", "caller", "prefix":
+		return "fields." + key
+	}
+	return key
+}
+
+// appendJSONKey adds the key to the object, the value is expected to be appended next
+func appendJSONKey(b []byte, key string) []byte {
+	if len(b) > 0 && b[len(b)-1] != '{' {
+		b = append(b, ',')
+	}
+	b = appendJSONString(b, key)
+	return append(b, ':')
+}
+
+// appendJSONValue adds the most natural JSON representation of v. Values with a kv.Render
+// rule are added as that string, except for a json.Marshaler (that is not registered) which
+// is added as is. Structs, maps, slices and arrays are added by appendJSONNested, so that any
+// nested LogValuer is resolved (i.e. a password field is redacted). Any value that can not be
+// marshaled as JSON (i.e. a channel or func) is added as its %v string value.
+func appendJSONValue(b []byte, v interface{}) []byte { return appendJSONDepth(b, v, 0) }
+
+// maxJSONDepth is how far nested values are followed before they are passed
+// to json.Marshal, this stops self referencing values from looping forever
+const maxJSONDepth = 16
+
+func appendJSONDepth(b []byte, v interface{}, depth int) []byte {
+	v = resolve(v)
+	switch vv := v.(type) {
+	case nil:
+		return append(b, "null"...)
+	case string:
+		return appendJSONString(b, vv)
+	}
+
+	if _, ok := v.(json.Marshaler); !ok || kv.IsRegistered(v) {
+		if s, ok := kv.Render(v); ok {
+			return appendJSONString(b, s)
+		}
+	}
+
+	if _, ok := v.(json.Marshaler); !ok && depth < maxJSONDepth {
+		if p, ok := appendJSONNested(b, reflect.ValueOf(v), depth); ok {
+			return p
+		}
+	}
+
+	p, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(b, fmt.Sprint(v))
+	}
+	return append(b, p...)
+}
+
+// appendJSONNested adds a struct, map, slice or array the same way as json.Marshal, except
+// that each nested value is added by appendJSONDepth. It returns false for any other value.
+func appendJSONNested(b []byte, rv reflect.Value, depth int) ([]byte, bool) {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return append(b, "null"...), true
+		}
+		return appendJSONDepth(b, rv.Elem().Interface(), depth+1), true
+	case reflect.Struct:
+		b = append(b, '{')
+		b = appendJSONStruct(b, rv, depth)
+		return append(b, '}'), true
+	case reflect.Map:
+		if rv.IsNil() {
+			return append(b, "null"...), true
+		}
+		var keys = rv.MapKeys()
+		var names = make([]string, len(keys))
+		for i, k := range keys {
+			switch k.Kind() {
+			case reflect.String:
+				names[i] = k.String()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				names[i] = strconv.FormatInt(k.Int(), 10)
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				names[i] = strconv.FormatUint(k.Uint(), 10)
+			default:
+				return b, false // i.e. an encoding.TextMarshaler key, which is left to json.Marshal
+			}
+		}
+		sort.Sort(jsonKeys{names, keys})
+		b = append(b, '{')
+		for i, k := range keys {
+			b = appendJSONKey(b, names[i])
+			b = appendJSONDepth(b, rv.MapIndex(k).Interface(), depth+1)
+		}
+		return append(b, '}'), true
+	case reflect.Slice:
+		if rv.IsNil() {
+			return append(b, "null"...), true
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return b, false // bytes are base64 encoded by json.Marshal
+		}
+		fallthrough
+	case reflect.Array:
+		b = append(b, '[')
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONDepth(b, rv.Index(i).Interface(), depth+1)
+		}
+		return append(b, ']'), true
+	}
+	return b, false
+}
+
+// appendJSONStruct adds the struct fields using the `json:"name,omitempty"` tag (or the field
+// name), the fields of an embedded struct without a tag are added as if they were in rv
+func appendJSONStruct(b []byte, rv reflect.Value, depth int) []byte {
+	var rt = rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		var sf, fv = rt.Field(i), rv.Field(i)
+
+		var name, opts = sf.Name, ""
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			name, opts = tag, ""
+			if idx := strings.Index(tag, ","); idx >= 0 {
+				name, opts = tag[:idx], tag[idx+1:]
+			}
+		}
+
+		if sf.Anonymous && name == sf.Name {
+			ev := fv
+			if ev.Kind() == reflect.Ptr {
+				if ev.IsNil() {
+					continue
+				}
+				ev = ev.Elem()
+			}
+			if ev.Kind() == reflect.Struct && ev.CanInterface() {
+				if _, ok := ev.Interface().(json.Marshaler); !ok {
+					b = appendJSONStruct(b, ev, depth+1)
+					continue
+				}
+			}
+		}
+		if sf.PkgPath != "" || !fv.CanInterface() { // unexported
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && jsonEmpty(fv) {
+			continue
+		}
+
+		b = appendJSONKey(b, name)
+		b = appendJSONDepth(b, fv.Interface(), depth+1)
+	}
+	return b
+}
+
+// jsonEmpty returns true for the values that json.Marshal leaves out with omitempty
+func jsonEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool:
+		return !rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return rv.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// jsonKeys sorts map keys by their string value, like json.Marshal
+type jsonKeys struct {
+	names []string
+	keys  []reflect.Value
+}
+
+func (s jsonKeys) Len() int           { return len(s.names) }
+func (s jsonKeys) Less(i, j int) bool { return s.names[i] < s.names[j] }
+func (s jsonKeys) Swap(i, j int) {
+	s.names[i], s.names[j] = s.names[j], s.names[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+const hex = "0123456789abcdef"
+
+// appendJSONString appends s as a quoted JSON string, escaping as needed
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	var start int
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' && c < utf8.RuneSelf {
+			i++
+			continue
+		}
+		if c < utf8.RuneSelf {
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
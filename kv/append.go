@@ -0,0 +1,47 @@
+package kv
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// Appender writes one K/V pair at a time in the same format as Marshal or MarshalLogfmt,
+// so a caller that has typed values (i.e. an int or a string) does not need to box
+// them in an interface{} and build a map just to marshal them.
+type Appender struct{ f format }
+
+var (
+	PlainAppender  = &Appender{plain}
+	LogfmtAppender = &Appender{logfmt}
+)
+
+// AppenderOf returns the Appender that writes the same format as fn, it returns
+// nil when fn is not Marshal or MarshalLogfmt
+func AppenderOf(fn func(interface{}) ([]byte, error)) *Appender {
+	if fn == nil {
+		return nil
+	}
+	switch reflect.ValueOf(fn).Pointer() {
+	case reflect.ValueOf(Marshal).Pointer():
+		return PlainAppender
+	case reflect.ValueOf(MarshalLogfmt).Pointer():
+		return LogfmtAppender
+	}
+	return nil
+}
+
+// Sep returns the separator that goes between each pair
+func (a *Appender) Sep() string { return a.f.sep }
+
+// Key writes the key followed by `=`
+func (a *Appender) Key(buf *bytes.Buffer, key string) {
+	a.f.key(buf, key)
+	buf.WriteByte('=')
+}
+
+// Value writes the value the same way as Marshal, using Render or `%v`
+func (a *Appender) Value(buf *bytes.Buffer, value interface{}) { a.f.value(buf, value) }
+
+// String writes s as a value, quoting it when the format needs it. Numbers and
+// booleans never need quoting so they can be written to buf directly.
+func (a *Appender) String(buf *bytes.Buffer, s string) { a.f.str(buf, s) }
@@ -0,0 +1,265 @@
+package kv
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ordered is implemented by a list of K/V pairs that should be marshaled in the
+// order of the list, rather than being sorted by key
+type Ordered interface {
+	Len() int
+	KV(i int) (key string, value interface{})
+}
+
+// ErrUnsupportedType is returned when the value passed to Marshal can not be broken into K/V pairs
+var ErrUnsupportedType = errors.New("kv: Marshal needs a map, struct or slice")
+
+// maxDepth is how far nested values are followed before being written as a single value,
+// this stops self referencing values from looping forever
+const maxDepth = 16
+
+// flatten breaks v into K/V pairs and passes each one to fn. A map is sorted by key, a struct
+// uses the `kv:"name,omitempty"` tag (or the field name) and a slice or array uses the index.
+// Nested values are joined to the parent key with a dot, so {"user": {"id": 7}} is `user.id=7`.
+func flatten(v interface{}, fn func(string, interface{})) error {
+	if o, ok := v.(Ordered); ok {
+		for i := 0; i < o.Len(); i++ {
+			k, v := o.KV(i)
+			pair(k, v, fn)
+		}
+		return nil
+	}
+
+	if kvs, ok := v.(map[string]interface{}); ok {
+		var ks = sPool.Get().([]string)
+		defer func() { ks = ks[:0]; sPool.Put(ks) }()
+
+		for key := range kvs {
+			ks = append(ks, key)
+		}
+
+		sort.Strings(ks)
+		for _, k := range ks {
+			pair(k, kvs[k], fn)
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map, reflect.Struct, reflect.Slice, reflect.Array:
+		if !isLeaf(rv) {
+			walk("", rv, fn, 0)
+			return nil
+		}
+	case reflect.Invalid:
+		return nil
+	}
+	return ErrUnsupportedType
+}
+
+// Each breaks v into K/V pairs the same way as Marshal and passes each one to fn, so a caller
+// can write the pairs itself (i.e. with color). It returns ErrUnsupportedType the same as Marshal.
+func Each(v interface{}, fn func(key string, value interface{})) error { return flatten(v, fn) }
+
+// logValuer matches the logger.LogValuer interface, so nested values are resolved as well
+type logValuer interface {
+	LogValue() interface{}
+}
+
+// pair passes a top level K/V pair to fn, only walking the value when it may be nested
+func pair(key string, value interface{}, fn func(string, interface{})) {
+	switch value.(type) {
+	case nil, string, bool, int, int64, uint, uint64, float64:
+		fn(key, value)
+	default:
+		walk(key, reflect.ValueOf(value), fn, 1)
+	}
+}
+
+func walk(prefix string, rv reflect.Value, fn func(string, interface{}), depth int) {
+	if rv.IsValid() && rv.CanInterface() && depth <= maxDepth {
+		if lv, ok := rv.Interface().(logValuer); ok && !isNilPtr(rv) {
+			walk(prefix, reflect.ValueOf(lv.LogValue()), fn, depth+1)
+			return
+		}
+	}
+
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() || isLeaf(rv) {
+			break
+		}
+		rv = rv.Elem()
+	}
+
+	if depth > maxDepth || isLeaf(rv) {
+		fn(prefix, value(rv))
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Len() == 0 {
+			fn(prefix, value(rv))
+			return
+		}
+		keys := rv.MapKeys()
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			names[i] = fmt.Sprint(k.Interface())
+		}
+		sort.Sort(byName{names, keys})
+		for i, k := range keys {
+			walk(join(prefix, names[i]), rv.MapIndex(k), fn, depth+1)
+		}
+	case reflect.Struct:
+		walkStruct(prefix, rv, fn, depth)
+	case reflect.Slice, reflect.Array:
+		if rv.Len() == 0 {
+			fn(prefix, value(rv))
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			walk(join(prefix, strconv.Itoa(i)), rv.Index(i), fn, depth+1)
+		}
+	default:
+		fn(prefix, value(rv))
+	}
+}
+
+func walkStruct(prefix string, rv reflect.Value, fn func(string, interface{}), depth int) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous { // unexported
+			continue
+		}
+
+		name, opts := sf.Name, ""
+		if tag, ok := sf.Tag.Lookup("kv"); ok {
+			if tag == "-" {
+				continue
+			}
+			name, opts = tag, ""
+			if idx := strings.Index(tag, ","); idx >= 0 {
+				name, opts = tag[:idx], tag[idx+1:]
+			}
+			if name == "" {
+				name = sf.Name
+			}
+		}
+
+		fv := rv.Field(i)
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmpty(fv) {
+			continue
+		}
+
+		// embedded structs without a tag have their fields promoted
+		if sf.Anonymous && sf.Tag.Get("kv") == "" {
+			ev := fv
+			if ev.Kind() == reflect.Ptr {
+				if ev.IsNil() {
+					continue
+				}
+				ev = ev.Elem()
+			}
+			if ev.Kind() == reflect.Struct && !isLeafType(ev.Type()) {
+				walkStruct(prefix, ev, fn, depth+1)
+				continue
+			}
+			if sf.PkgPath != "" {
+				continue
+			}
+		}
+
+		walk(join(prefix, name), fv, fn, depth+1)
+	}
+}
+
+// isLeaf returns true for values that describe themselves as a single
+// value, so they are not broken apart (i.e. a time.Time or an error)
+func isLeaf(rv reflect.Value) bool {
+	if !rv.IsValid() || !rv.CanInterface() {
+		return true
+	}
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return true
+		}
+	}
+	return isLeafType(rv.Type())
+}
+
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	bytesType         = reflect.TypeOf([]byte(nil))
+)
+
+// isLeafType returns true for any type that has a Render rule
+func isLeafType(t reflect.Type) bool {
+	if _, ok := renderers.Load().(map[reflect.Type]func(interface{}) string)[t]; ok {
+		return true
+	}
+	return t == bytesType || t.Implements(errorType) || t.Implements(stringerType) ||
+		t.Implements(textMarshalerType) || t.Implements(jsonMarshalerType)
+}
+
+func isNilPtr(rv reflect.Value) bool {
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+func isEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		return rv.Len() == 0
+	}
+	return rv.IsZero()
+}
+
+func value(rv reflect.Value) interface{} {
+	if !rv.IsValid() {
+		return nil
+	}
+	if !rv.CanInterface() {
+		return fmt.Sprint(rv)
+	}
+	return rv.Interface()
+}
+
+func join(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// byName sorts map keys by their string value
+type byName struct {
+	names []string
+	keys  []reflect.Value
+}
+
+func (s byName) Len() int           { return len(s.names) }
+func (s byName) Less(i, j int) bool { return s.names[i] < s.names[j] }
+func (s byName) Swap(i, j int) {
+	s.names[i], s.names[j] = s.names[j], s.names[i]
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
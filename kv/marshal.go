@@ -3,8 +3,8 @@ package kv
 import (
 	"bytes"
 	"fmt"
-	"sort"
 	"sync"
+	"unicode/utf8"
 )
 
 var bPool = sync.Pool{
@@ -19,29 +19,136 @@ var sPool = sync.Pool{
 	},
 }
 
+// format defines how the K/V pairs are written
+type format struct {
+	sep   string
+	key   func(*bytes.Buffer, string)
+	value func(*bytes.Buffer, interface{})
+	str   func(*bytes.Buffer, string)
+}
+
+var (
+	plain  = format{sep: ", ", key: writePlainKey, value: writePlainValue, str: writePlainString}
+	logfmt = format{sep: " ", key: writeLogfmtKey, value: writeLogfmtValue, str: writeLogfmtString}
+)
+
+// Marshal returns the K/V pairs of v as `key=value` separated by a comma and
+// a space, the keys are sorted and the values are written using Render or `%v`. The value
+// v can be a map, struct, slice or a pointer to one, see flatten for the details.
 func Marshal(v interface{}) (out []byte, err error) {
-	// cheat and only accept maps for now
-	// TODO(njones): make this accept structs as well.
+	return marshal(v, plain)
+}
+
+// MarshalLogfmt returns the K/V pairs of v in the logfmt format. The pairs are
+// separated by a single space, and any value that contains a space, `=`, a quote
+// or a control character is quoted and escaped so it can be read back by Unmarshal.
+func MarshalLogfmt(v interface{}) (out []byte, err error) {
+	return marshal(v, logfmt)
+}
 
+func marshal(v interface{}, f format) (out []byte, err error) {
 	var comma string
 	var sb = bPool.Get().(*bytes.Buffer)
 	defer func() { sb.Reset(); bPool.Put(sb) }()
 
-	switch kvs := v.(type) {
-	case map[string]interface{}:
-		var ks = sPool.Get().([]string)
-		defer func() { ks = ks[:0]; sPool.Put(ks) }()
+	err = flatten(v, func(key string, value interface{}) {
+		sb.WriteString(comma)
+		f.key(sb, key)
+		sb.WriteByte('=')
+		f.value(sb, value)
+		comma = f.sep
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(nil), sb.Bytes()...), err
+}
+
+func writePlainKey(sb *bytes.Buffer, key string) { sb.WriteString(key) }
+
+func writePlainString(sb *bytes.Buffer, s string) { sb.WriteString(s) }
+
+func writePlainValue(sb *bytes.Buffer, value interface{}) {
+	if s, ok := Render(value); ok {
+		sb.WriteString(s)
+		return
+	}
+	fmt.Fprintf(sb, "%v", value)
+}
 
-		for key := range kvs {
-			ks = append(ks, key)
+// writeLogfmtKey writes the key, any character that would break
+// parsing (i.e. a space, `=` or quote) is written as an underscore
+func writeLogfmtKey(sb *bytes.Buffer, key string) {
+	if key == "" {
+		sb.WriteByte('_')
+		return
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || r == 0x7f {
+			r = '_'
 		}
+		sb.WriteRune(r)
+	}
+}
+
+// writeLogfmtValue writes the value, quoting it when needed
+func writeLogfmtValue(sb *bytes.Buffer, value interface{}) {
+	var s string
+	switch vv := value.(type) {
+	case string:
+		s = vv
+	default:
+		var ok bool
+		if s, ok = Render(vv); !ok {
+			s = fmt.Sprint(vv)
+		}
+	}
+	writeLogfmtString(sb, s)
+}
 
-		sort.Strings(ks)
-		for _, k := range ks {
-			fmt.Fprintf(sb, "%s%s=%v", comma, k, kvs[k])
-			comma = ", "
+const hex = "0123456789abcdef"
+
+// needsQuote returns true when the logfmt value must be quoted
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || r == 0x7f {
+			return true
 		}
 	}
+	return false
+}
 
-	return sb.Bytes(), err
+func writeLogfmtString(sb *bytes.Buffer, s string) {
+	if !needsQuote(s) {
+		sb.WriteString(s)
+		return
+	}
+
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < ' ' || r == 0x7f {
+				sb.WriteString(`\u00`)
+				sb.WriteByte(hex[r>>4])
+				sb.WriteByte(hex[r&0xF])
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
 }
@@ -0,0 +1,221 @@
+package kv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testUser struct {
+	ID     int               `kv:"id"`
+	Name   string            `kv:"name,omitempty"`
+	Email  string            `kv:"-"`
+	Tags   []string          `kv:"tags"`
+	Meta   map[string]string `kv:"meta,omitempty"`
+	Parent *testUser         `kv:"parent,omitempty"`
+	Joined time.Time         `kv:"joined"`
+	secret string
+}
+
+type testAudit struct {
+	testUser
+	Action string
+}
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		marshal func(interface{}) ([]byte, error)
+		input   interface{}
+		want    string
+	}{
+		{
+			name:    "plain",
+			marshal: Marshal,
+			input:   map[string]interface{}{"the": "quick", "brown": 3, "fox": true},
+			want:    "brown=3, fox=true, the=quick",
+		},
+		{
+			name:    "plain (no quoting)",
+			marshal: Marshal,
+			input:   map[string]interface{}{"the": "quick brown", "fox": "a=b, c"},
+			want:    "fox=a=b, c, the=quick brown",
+		},
+		{
+			name:    "plain nested values",
+			marshal: Marshal,
+			input: map[string]interface{}{
+				"user": testUser{ID: 7, Email: "x@y.z", Tags: []string{"a", "b"}, Joined: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), secret: "shh"},
+				"map":  map[string]interface{}{"b": 2, "a": map[int]bool{1: true}},
+				"nil":  (*testUser)(nil),
+				"err":  errors.New("bad thing"),
+			},
+			want: "err=bad thing, map.a.1=true, map.b=2, nil=<nil>, user.id=7, user.tags.0=a, user.tags.1=b, user.joined=2020-01-02T03:04:05Z",
+		},
+		{
+			name:    "plain struct pointer",
+			marshal: Marshal,
+			input:   &testUser{ID: 1, Name: "child", Tags: []string{}, Meta: map[string]string{"k": "v"}, Parent: &testUser{ID: 2}},
+			want:    "id=1, name=child, tags=[], meta.k=v, parent.id=2, parent.tags=[], parent.joined=0001-01-01T00:00:00Z, joined=0001-01-01T00:00:00Z",
+		},
+		{
+			name:    "plain embedded struct",
+			marshal: Marshal,
+			input:   testAudit{testUser: testUser{ID: 3}, Action: "login"},
+			want:    "id=3, tags=[], joined=0001-01-01T00:00:00Z, Action=login",
+		},
+		{
+			name:    "plain tag with many options",
+			marshal: Marshal,
+			input: struct {
+				A string `kv:"a,omitempty,other"`
+				B int    `kv:"b,other,omitempty"`
+				C int    `kv:"c,other"`
+			}{},
+			want: "c=0",
+		},
+		{
+			name:    "plain slice",
+			marshal: Marshal,
+			input:   []interface{}{"a", []int{1, 2}},
+			want:    "0=a, 1.0=1, 1.1=2",
+		},
+		{
+			name:    "logfmt",
+			marshal: MarshalLogfmt,
+			input:   map[string]interface{}{"the": "quick", "brown": 3, "fox": true},
+			want:    "brown=3 fox=true the=quick",
+		},
+		{
+			name:    "logfmt quoting",
+			marshal: MarshalLogfmt,
+			input: map[string]interface{}{
+				"space":   "quick brown",
+				"equals":  "a=b",
+				"comma":   "a,b",
+				"quote":   `say "hi"`,
+				"newline": "line1\nline2",
+				"empty":   "",
+				"ctrl":    "\x01",
+			},
+			want: `comma=a,b ctrl="\u0001" empty="" equals="a=b" newline="line1\nline2" quote="say \"hi\"" space="quick brown"`,
+		},
+		{
+			name:    "logfmt bad keys",
+			marshal: MarshalLogfmt,
+			input:   map[string]interface{}{"the key": 1, "a=b": 2, "": 3},
+			want:    "_=3 a_b=2 the_key=1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			have, err := test.marshal(test.input)
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if string(have) != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have, test.want)
+			}
+		})
+	}
+}
+
+func TestMarshalUnsupported(t *testing.T) {
+	for _, v := range []interface{}{1, "string", time.Second} {
+		if _, err := Marshal(v); err != ErrUnsupportedType {
+			t.Errorf("\nhave: %v\nwant: %v\n", err, ErrUnsupportedType)
+		}
+	}
+	if have, err := Marshal(nil); err != nil || len(have) != 0 {
+		t.Errorf("\nhave: %q %v\nwant: \"\" <nil>\n", have, err)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+		err   string
+	}{
+		{
+			name:  "basic",
+			input: "brown=3 fox=true the=quick",
+			want:  map[string]string{"brown": "3", "fox": "true", "the": "quick"},
+		},
+		{
+			name:  "quoted",
+			input: `comma=a,b ctrl="\u0001" empty="" equals="a=b" newline="line1\nline2" quote="say \"hi\"" space="quick brown"`,
+			want: map[string]string{
+				"space":   "quick brown",
+				"equals":  "a=b",
+				"comma":   "a,b",
+				"quote":   `say "hi"`,
+				"newline": "line1\nline2",
+				"empty":   "",
+				"ctrl":    "\x01",
+			},
+		},
+		{
+			name:  "bare key and extra space",
+			input: "  debug  the=quick\n",
+			want:  map[string]string{"debug": "", "the": "quick"},
+		},
+		{
+			name:  "unterminated quote",
+			input: `the="quick`,
+			err:   "kv: unterminated quoted value at offset 10",
+		},
+		{
+			name:  "missing space",
+			input: `the="quick"brown=fox`,
+			err:   "kv: missing space after quoted value at offset 11",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			var have map[string]string
+			err := Unmarshal([]byte(test.input), &have)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					tt.Errorf("\nhave: %v\nwant: %s\n", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				tt.Fatal(err)
+			}
+			if !reflect.DeepEqual(have, test.want) {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have, test.want)
+			}
+		})
+	}
+
+	if err := Unmarshal([]byte("a=b"), []string{}); err != ErrUnmarshalType {
+		t.Errorf("\nhave: %v\nwant: %v\n", err, ErrUnmarshalType)
+	}
+}
+
+func TestLogfmtRoundTrip(t *testing.T) {
+	want := map[string]interface{}{
+		"msg":   "the quick\tbrown \"fox\"\njumped=over, the lazy dog\\",
+		"uni":   "héllo wörld ☃",
+		"plain": "value",
+	}
+
+	data, err := MarshalLogfmt(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := make(map[string]interface{})
+	if err := Unmarshal(data, have); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("\nhave: %q\nwant: %q\n", have, want)
+	}
+}
@@ -0,0 +1,111 @@
+package kv
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// renderers holds a map[reflect.Type]func(interface{}) string of the registered types,
+// the map is replaced (never changed) on each Register so it can be read without a lock
+var renderers atomic.Value
+
+var renderersMu sync.Mutex
+
+func init() { renderers.Store(map[reflect.Type]func(interface{}) string{}) }
+
+// Register sets fn as the way to render any value with the same type as example. A
+// registered type is used ahead of all of the built-in rules, and a nil fn removes
+// the type. This should be called during init, before any values are rendered.
+func Register(example interface{}, fn func(interface{}) string) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+
+	old := renderers.Load().(map[reflect.Type]func(interface{}) string)
+	m := make(map[reflect.Type]func(interface{}) string, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+
+	t := reflect.TypeOf(example)
+	if fn == nil {
+		delete(m, t)
+	} else {
+		m[t] = fn
+	}
+	renderers.Store(m)
+}
+
+// IsRegistered returns true if the type of v has been registered
+func IsRegistered(v interface{}) bool {
+	_, ok := renderers.Load().(map[reflect.Type]func(interface{}) string)[reflect.TypeOf(v)]
+	return ok
+}
+
+// Render returns the string for v using the first rule that matches:
+//
+//	a registered type     - the registered function
+//	error                 - Error()
+//	time.Time             - RFC3339 with nanoseconds
+//	time.Duration         - String() (i.e. 1m30s)
+//	[]byte                - as a string when it is valid UTF-8, otherwise base64
+//	json.Marshaler        - the JSON text
+//	encoding.TextMarshaler - the text
+//	fmt.Stringer          - String()
+//
+// It returns false if no rule matches, in which case the value should be written as is.
+func Render(v interface{}) (s string, ok bool) {
+	if v == nil {
+		return "", false
+	}
+
+	defer func() {
+		// like fmt, a nil receiver that panics is written as <nil>
+		if r := recover(); r != nil {
+			s, ok = fmt.Sprintf("!PANIC(%v)", r), true
+			if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+				s = "<nil>"
+			}
+		}
+	}()
+
+	if fn, ok := renderers.Load().(map[reflect.Type]func(interface{}) string)[reflect.TypeOf(v)]; ok {
+		return fn(v), true
+	}
+
+	switch vv := v.(type) {
+	case error:
+		return vv.Error(), true
+	case time.Time:
+		return vv.Format(time.RFC3339Nano), true
+	case time.Duration:
+		return vv.String(), true
+	case []byte:
+		if utf8.Valid(vv) {
+			return string(vv), true
+		}
+		return base64.StdEncoding.EncodeToString(vv), true
+	case json.Marshaler:
+		p, err := vv.MarshalJSON()
+		if err != nil {
+			return fmt.Sprintf("!ERROR(%v)", err), true
+		}
+		return string(p), true
+	case encoding.TextMarshaler:
+		p, err := vv.MarshalText()
+		if err != nil {
+			return fmt.Sprintf("!ERROR(%v)", err), true
+		}
+		return string(p), true
+	case fmt.Stringer:
+		return vv.String(), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,62 @@
+package kv
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+type password string
+
+type jsonOnly struct{ A int }
+
+func (j jsonOnly) MarshalJSON() ([]byte, error) { return []byte(`{"a":1}`), nil }
+
+type stringerPtr struct{ s string }
+
+func (p *stringerPtr) String() string { return "ptr" + p.s }
+
+func TestRender(t *testing.T) {
+	Register(password(""), func(interface{}) string { return "***" })
+	defer Register(password(""), nil)
+
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+		ok    bool
+	}{
+		{name: "error", input: errors.New("bad thing"), want: "bad thing", ok: true},
+		{name: "time.Time", input: time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC), want: "2020-01-02T03:04:05.000000006Z", ok: true},
+		{name: "time.Duration", input: 90 * time.Second, want: "1m30s", ok: true},
+		{name: "[]byte", input: []byte("hello"), want: "hello", ok: true},
+		{name: "[]byte binary", input: []byte{0xff, 0x00}, want: "/wA=", ok: true},
+		{name: "json.Marshaler", input: jsonOnly{}, want: `{"a":1}`, ok: true},
+		{name: "json.RawMessage", input: json.RawMessage(`[1,2]`), want: `[1,2]`, ok: true},
+		{name: "encoding.TextMarshaler", input: net.IPv4(127, 0, 0, 1), want: "127.0.0.1", ok: true},
+		{name: "fmt.Stringer", input: &stringerPtr{}, want: "ptr", ok: true},
+		{name: "nil fmt.Stringer", input: (*stringerPtr)(nil), want: "<nil>", ok: true},
+		{name: "registered", input: password("secret"), want: "***", ok: true},
+		{name: "int", input: 3, ok: false},
+		{name: "nil", input: nil, ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			have, ok := Render(test.input)
+			if ok != test.ok {
+				tt.Errorf("\nhave: %t\nwant: %t\n", ok, test.ok)
+			}
+			if have != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have, test.want)
+			}
+		})
+	}
+
+	have, _ := Marshal(map[string]interface{}{"pass": password("secret"), "raw": []byte("a b"), "nil": (*stringerPtr)(nil)})
+	if want := "nil=<nil>, pass=***, raw=a b"; string(have) != want {
+		t.Errorf("\nhave: %q\nwant: %q\n", have, want)
+	}
+}
@@ -0,0 +1,147 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnmarshalType is returned when Unmarshal is passed a value it can not fill
+var ErrUnmarshalType = errors.New("kv: Unmarshal needs a map[string]string or map[string]interface{}")
+
+// SyntaxError describes where the logfmt data could not be parsed
+type SyntaxError struct {
+	Offset int // the byte offset where the error occurred
+	msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return "kv: " + e.msg + " at offset " + strconv.Itoa(e.Offset)
+}
+
+// Unmarshal parses logfmt data (as written by MarshalLogfmt) and stores the pairs in v,
+// which must be a map[string]string or a map[string]interface{} (or a pointer to one).
+// A key without a `=` is stored with an empty string value, and all values are strings.
+func Unmarshal(data []byte, v interface{}) error {
+	var set func(key, value string)
+
+	switch m := v.(type) {
+	case map[string]string:
+		set = func(k, v string) { m[k] = v }
+	case map[string]interface{}:
+		set = func(k, v string) { m[k] = v }
+	case *map[string]string:
+		if *m == nil {
+			*m = make(map[string]string)
+		}
+		set = func(k, v string) { (*m)[k] = v }
+	case *map[string]interface{}:
+		if *m == nil {
+			*m = make(map[string]interface{})
+		}
+		set = func(k, v string) { (*m)[k] = v }
+	default:
+		return ErrUnmarshalType
+	}
+
+	var s = string(data)
+	for i := 0; i < len(s); {
+		if isSpace(s[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' {
+			if s[i] == '"' {
+				return &SyntaxError{Offset: i, msg: "unexpected quote in key"}
+			}
+			i++
+		}
+		key := s[start:i]
+		if key == "" {
+			return &SyntaxError{Offset: i, msg: "missing key"}
+		}
+
+		if i >= len(s) || s[i] != '=' {
+			set(key, "")
+			continue
+		}
+		i++ // skip the `=`
+
+		if i < len(s) && s[i] == '"' {
+			value, n, err := unquote(s[i:])
+			if err != nil {
+				err.Offset += i
+				return err
+			}
+			set(key, value)
+			i += n
+			if i < len(s) && !isSpace(s[i]) {
+				return &SyntaxError{Offset: i, msg: "missing space after quoted value"}
+			}
+			continue
+		}
+
+		start = i
+		for i < len(s) && !isSpace(s[i]) {
+			if s[i] == '"' || s[i] == '=' {
+				return &SyntaxError{Offset: i, msg: fmt.Sprintf("unexpected %q in value", s[i])}
+			}
+			i++
+		}
+		set(key, s[start:i])
+	}
+
+	return nil
+}
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+
+// unquote reads a quoted value from the start of s, returning the
+// value and the number of bytes read including both quotes
+func unquote(s string) (string, int, *SyntaxError) {
+	var sb strings.Builder
+	for i := 1; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			return sb.String(), i + 1, nil
+		case c == '\\':
+			if i+1 >= len(s) {
+				return "", 0, &SyntaxError{Offset: i, msg: "unterminated escape"}
+			}
+			switch s[i+1] {
+			case '"', '\\', '/':
+				sb.WriteByte(s[i+1])
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if i+6 > len(s) {
+					return "", 0, &SyntaxError{Offset: i, msg: "short unicode escape"}
+				}
+				r, err := strconv.ParseUint(s[i+2:i+6], 16, 32)
+				if err != nil {
+					return "", 0, &SyntaxError{Offset: i, msg: "invalid unicode escape"}
+				}
+				sb.WriteRune(rune(r))
+				i += 6
+				continue
+			default:
+				return "", 0, &SyntaxError{Offset: i, msg: fmt.Sprintf("invalid escape %q", s[i+1])}
+			}
+			i += 2
+		default:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			sb.WriteRune(r)
+			i += size
+		}
+	}
+	return "", 0, &SyntaxError{Offset: len(s), msg: "unterminated quoted value"}
+}
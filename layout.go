@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+)
+
+// DefaultLayout is the layout of the default encoder, the level color is around each run
+// of words next to each other with the caller, level, prefix or message in them
+const DefaultLayout = "{time} {caller} {level} {prefix} {msg} {fields}"
+
+type segment uint8
+
+const (
+	sText segment = iota
+	sTime
+	sCaller
+	sLevel
+	sPrefix
+	sMsg
+	sFields
+)
+
+var segments = map[string]segment{
+	"time":   sTime,
+	"caller": sCaller,
+	"level":  sLevel,
+	"prefix": sPrefix,
+	"msg":    sMsg,
+	"fields": sFields,
+}
+
+// colored returns true for the segments that are written in the level color
+func (s segment) colored() bool { return s >= sCaller && s <= sMsg }
+
+// layoutPart is either literal text or a segment of the line
+type layoutPart struct {
+	text string
+	seg  segment
+}
+
+// layout is the line broken into space separated words, a word where all of the
+// segments are empty is left out of the line (along with its space)
+type layout [][]layoutPart
+
+// parseLayout breaks the layout into words, a placeholder that is not a segment is kept as text
+func parseLayout(s string) layout {
+	var words = strings.Split(s, " ")
+	var l = make(layout, 0, len(words))
+	for _, word := range words {
+		var parts []layoutPart
+		holes(word, func(text string) {
+			parts = append(parts, layoutPart{text: text})
+		}, func(name string) {
+			if seg, ok := segments[name]; ok {
+				parts = append(parts, layoutPart{seg: seg})
+				return
+			}
+			parts = append(parts, layoutPart{text: "{" + name + "}"})
+		})
+		l = append(l, parts)
+	}
+	return l
+}
+
+// kept returns true when the word has text only, or a segment that is not empty. The
+// message is never empty, so a line always has its message (even if it's blank).
+func (enc *textEncoder) kept(word []layoutPart, e *Entry, kv []byte, typed []field) bool {
+	var text = true
+	for _, p := range word {
+		switch p.seg {
+		case sText:
+			continue
+		case sTime:
+			if len(e.Stamp) > 0 {
+				return true
+			}
+		case sCaller:
+			if len(e.Caller) > 0 {
+				return true
+			}
+		case sLevel:
+			if e.Label != nil {
+				return true
+			}
+		case sPrefix:
+			if e.Prefix != nil {
+				return true
+			}
+		case sMsg:
+			return true
+		case sFields:
+			if len(kv) > 0 || len(typed) > 0 {
+				return true
+			}
+		}
+		text = false
+	}
+	return text
+}
+
+// encodeLayout writes the line in the order of the layout, the color is around each
+// run of colored words, so the words in between (i.e. the time or fields) are plain
+func (enc *textEncoder) encodeLayout(b *bytes.Buffer, e *Entry, kv []byte, typed []field) {
+	var words = make([]int, 0, len(enc.layout)) // the words that are kept
+	var colored = make([]bool, len(enc.layout))
+	for i, word := range enc.layout {
+		if !enc.kept(word, e, kv, typed) {
+			continue
+		}
+		words = append(words, i)
+		for _, p := range word {
+			colored[i] = colored[i] || (len(e.Color) > 0 && p.seg.colored())
+		}
+	}
+
+	for n, i := range words {
+		if n > 0 {
+			b.Write(space)
+		}
+		if colored[i] && (n == 0 || !colored[words[n-1]]) {
+			b.Write(e.Color)
+		}
+		for _, p := range enc.layout[i] {
+			switch p.seg {
+			case sText:
+				b.WriteString(p.text)
+			case sTime:
+				b.Write(e.Stamp)
+			case sCaller:
+				b.WriteString(e.Caller)
+			case sLevel:
+				b.Write(e.Label)
+			case sPrefix:
+				b.Write(e.Prefix)
+			case sMsg:
+				b.WriteString(e.Message)
+			case sFields:
+				enc.fields(b, kv, typed)
+			}
+		}
+		if colored[i] && (n == len(words)-1 || !colored[words[n+1]]) {
+			b.Write(colorEnd)
+		}
+	}
+	b.Write(newline)
+}
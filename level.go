@@ -1,21 +1,211 @@
 package logger
 
-// logLevel is defined so the we can suppress different levels as needed
-type logLevel uint16
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/njones/logger/color"
+)
+
+// logLevel is defined so the we can suppress different levels as needed. The bits are
+// not in severity order, so the severity tag is used when comparing with SetLevel. The
+// severities have gaps so more levels can be added between the existing ones.
+type logLevel uint32
 
 // print is the Print(f,ln) log level (which is the same as Info), but not exported
-const print logLevel = 1 << iota //`short:"INF" long:"Info" color:"green"`
+const print logLevel = 1 << iota //`short:"INF" long:"Info" color:"green" severity:"30" syslog:"6"`
 
 // A bitwise representation of the different log levels
 // NOTE: the comment tags are used when using go generate to
 // generate parts of the logging code
+//
+// The syslog tag is the RFC 5424 severity that the level maps to (see Syslog):
+//
+//	Emergency, Panic  0  system is unusable
+//	Alert             1  action must be taken immediately
+//	Critical, Fatal   2  critical conditions
+//	Error             3  error conditions
+//	Warn              4  warning conditions
+//	Notice            5  normal but significant condition
+//	Info, HTTP        6  informational messages
+//	Debug, Trace      7  debug-level messages
 const (
-	Info  logLevel = 1 << iota //`short:"INF" color:"green"`
-	Warn                       //`short:"WRN" color:"yellow"`
-	Debug                      //`short:"DBG" color:"cyan"`
-	Error                      //`short:"ERR" color:"magenta"`
-	Trace                      //`short:"TRC" color:"blue"`
-	Fatal                      //`short:"FAT" color:"red"`
-	Panic                      //`short:"PAN" color:"red"`
-	HTTP                       //`short:"-" long:"-" color:"-" fn:"ln"`
+	Info  logLevel = 1 << iota //`short:"INF" color:"green" severity:"30" syslog:"6"`
+	Warn                       //`short:"WRN" color:"yellow" severity:"50" syslog:"4"`
+	Debug                      //`short:"DBG" color:"cyan" severity:"20" syslog:"7"`
+	Error                      //`short:"ERR" color:"magenta" severity:"60" syslog:"3"`
+	Trace                      //`short:"TRC" color:"blue" severity:"10" syslog:"7"`
+	Fatal                      //`short:"FAT" color:"red" severity:"80" syslog:"2"`
+	Panic                      //`short:"PAN" color:"red" severity:"90" syslog:"0"`
+	HTTP                       //`short:"-" long:"-" color:"-" fn:"ln" severity:"30" syslog:"6"`
+
+	Notice    //`short:"NTC" color:"white" severity:"40" syslog:"5"`
+	Critical  //`short:"CRT" color:"red" severity:"70" syslog:"2"`
+	Alert     //`short:"ALR" color:"red" severity:"85" syslog:"1"`
+	Emergency //`short:"EMR" color:"red" severity:"100" syslog:"0"`
 )
+
+// The errors returned from RegisterLevel
+var (
+	ErrLevelName     = errors.New("logger: a level name is needed")
+	ErrLevelExists   = errors.New("logger: the level name is already used")
+	ErrTooManyLevels = errors.New("logger: there are no more levels to register")
+)
+
+// Syslog returns the RFC 5424 severity (0 to 7) for the level. A level from
+// RegisterLevel is mapped using its severity, in the same order as the built-in levels.
+func (ll logLevel) Syslog() int {
+	if s, ok := llSyslog[ll]; ok {
+		return s
+	}
+	switch severity := ll.severity(); {
+	case severity >= Emergency.severity():
+		return 0
+	case severity >= Alert.severity():
+		return 1
+	case severity >= Critical.severity():
+		return 2
+	case severity >= Error.severity():
+		return 3
+	case severity >= Warn.severity():
+		return 4
+	case severity >= Notice.severity():
+		return 5
+	case severity >= Info.severity():
+		return 6
+	}
+	return 7
+}
+
+// level holds the values for a registered level, the labels are in the
+// display order: default, box, short and short box
+type level struct {
+	name     string
+	labels   [4]levelize
+	color    colorize
+	severity int
+}
+
+func (l level) levelize(display int) levelize {
+	if display < 0 || display >= len(l.labels) {
+		return nil
+	}
+	return l.labels[display]
+}
+
+// levels holds a map[logLevel]level of the registered levels, the map is
+// replaced (never changed) on each RegisterLevel so it can be read without a lock
+var levels atomic.Value
+
+var levelsMu sync.Mutex
+
+// registered returns the registered level, the levels are not loaded during
+// init because a level can be registered as a package variable (before init)
+func (ll logLevel) registered() level {
+	m, _ := levels.Load().(map[logLevel]level)
+	return m[ll]
+}
+
+// RegisterLevel adds a new level at runtime, that can be logged using Log(f,ln,t) or Event. The
+// name is used for the label (i.e. AUDIT:) and String(), and the short name is used for the short
+// display styles (the first three letters of the name when it's empty). The severity is compared
+// with SetLevel, see level.go for the severity of the built-in levels. This should be called
+// during init, before any lines are logged.
+func RegisterLevel(name, short string, c color.Foreground, severity int) (logLevel, error) {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return 0, ErrLevelName
+	}
+	if short = strings.ToUpper(strings.TrimSpace(short)); short == "" {
+		short = strings.ToUpper(name)
+		if len(short) > 3 {
+			short = short[:3]
+		}
+	}
+
+	for _, n := range llNames {
+		if n == name {
+			return 0, ErrLevelExists
+		}
+	}
+
+	old, _ := levels.Load().(map[logLevel]level)
+	m := make(map[logLevel]level, len(old)+1)
+	for k, v := range old {
+		if v.name == name {
+			return 0, ErrLevelExists
+		}
+		m[k] = v
+	}
+
+	ll := logLevel(llNext) << uint(len(old))
+	if ll == 0 {
+		return 0, ErrTooManyLevels
+	}
+
+	long := strings.ToUpper(name)
+	m[ll] = level{
+		name:     name,
+		labels:   [4]levelize{levelize(long + ":"), levelize("[" + long + "]"), levelize(short + ":"), levelize("[" + short + "]")},
+		color:    colorize(c.ToESC()),
+		severity: severity,
+	}
+	levels.Store(m)
+
+	return ll, nil
+}
+
+// MustRegisterLevel is the same as RegisterLevel, but it panics on an error. It
+// is for registering levels as package variables.
+func MustRegisterLevel(name, short string, c color.Foreground, severity int) logLevel {
+	ll, err := RegisterLevel(name, short, c, severity)
+	if err != nil {
+		panic(err)
+	}
+	return ll
+}
+
+// levelLabels holds the user-defined labels from WithLevelLabels, each label is
+// kept after the first time a level is used, so fn is called once per level
+type levelLabels struct {
+	fn    func(logLevel) string
+	mu    sync.Mutex
+	cache atomic.Value // map[logLevel]levelize replaced (never changed) on each new label
+}
+
+func (l *levelLabels) label(ll logLevel) levelize {
+	if label, ok := l.load()[ll]; ok {
+		return label
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	old := l.load()
+	if label, ok := old[ll]; ok {
+		return label
+	}
+
+	m := make(map[logLevel]levelize, len(old)+1)
+	for k, v := range old {
+		m[k] = v
+	}
+	if label := l.fn(ll); label != "" {
+		m[ll] = levelize(label)
+	} else {
+		m[ll] = nil
+	}
+	l.cache.Store(m)
+
+	return m[ll]
+}
+
+func (l *levelLabels) load() map[logLevel]levelize {
+	m, _ := l.cache.Load().(map[logLevel]levelize)
+	return m
+}
@@ -1,10 +1,15 @@
 package logger
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type deferFunc func()
@@ -14,6 +19,7 @@ type setize interface {
 }
 
 type colorize []byte
+type depthize int
 type formatize string
 type levelize []byte
 type timeize []byte
@@ -25,10 +31,19 @@ func (x colorize) set(ln *line) {
 	}
 }
 
+func (x depthize) set(ln *line)  { ln.depth = int(x) }
 func (x formatize) set(ln *line) { ln.format = string(x) }
 func (x levelize) set(ln *line)  { ln.prefixLevel = []byte(x) }
 func (x timeize) set(ln *line)   { ln.time = []byte(x) }
-func (w writeize) set(ln *line)  { ln.out.w = io.MultiWriter(ln.out.w, w) }
+func (w writeize) set(ln *line) {
+	ln.out.w = io.MultiWriter(ln.out.w, w)
+	if ln.out.nw == nil {
+		ln.out.nw = w
+		return
+	}
+	ln.out.nw = io.MultiWriter(ln.out.nw, w)
+}
+func (ll logLevel) set(ln *line) { ln.level = ll }
 
 type dropCRWriter struct {
 	w io.Writer
@@ -46,119 +61,213 @@ type line struct {
 	do printKind
 
 	flags int
-	depth int
+	depth int // the runtime.Caller depth from Output, or -1 for the first caller outside of the logger
+	level logLevel
 
+	now         time.Time
 	time        []byte
 	color       []byte
 	prefixLevel []byte
 	prefixUser  []byte
+	labeled     bool // the prefixLevel is from WithLevelLabels
 
 	format string
 	v      []interface{}
-	kv     string
+	kv     []KeyVal
+	places []KeyVal // the template placeholder values, before the K/V pairs replace any of them
+
+	typed []field // the Event fields
+
+	ts    []byte       // the buffer for the timestamp
+	buf   bytes.Buffer // the buffer for the encoded line
+	msg   bytes.Buffer
+	entry Entry
+	enc   Encoder
+	text  textEncoder // the default encoder, kept here so it doesn't allocate
 
 	out struct {
-		w  io.Writer
-		cw io.Writer // color writer
+		w  io.Writer // all writers
+		cw io.Writer // color writers
+		nw io.Writer // no color writers
 		dw *dropCRWriter
 	}
 	err error
 }
 
 func (ln *line) write() error {
-	defer ln.writeNewLine()
-	defer ln.writeKVPairs()
-	defer ln.writeColorEnd()
-
-	ln.writeTime()
-	ln.writeColor()
-	ln.writeFilename()
-	ln.writePrefixLevel()
-	ln.writePrefixUser()
-	ln.writePrint()
+	var e = &ln.entry
+
+	e.Time = ln.now
+	e.Stamp = ln.time
+	e.Level = ln.level
+	e.Label = ln.prefixLevel
+	e.labeled = ln.labeled
+	e.Prefix = ln.prefixUser
+	e.Caller = ln.caller()
+	e.Message = ln.message()
+	e.Fields = ln.kv
+	e.typed = ln.typed
+
+	if ln.err != nil {
+		return ln.err
+	}
+
+	if _, ok := ln.enc.(typedEncoder); !ok && len(e.typed) > 0 {
+		e.Fields, e.typed = appendKV(e.Fields, e.typed), nil
+	}
+
+	if _, ok := ln.enc.(styledEncoder); !ok && len(ln.color) == 0 {
+		return ln.flush(ln.out.w, e)
+	}
+
+	if ln.out.cw != nil {
+		e.Color, e.colored = ln.color, true
+		if ln.err = ln.flush(ln.out.cw, e); ln.err != nil {
+			return ln.err
+		}
+	}
+
+	if ln.out.nw != nil {
+		e.Color, e.colored = nil, false
+		ln.err = ln.flush(ln.out.nw, e)
+	}
+
 	return ln.err
 }
 
-func (ln *line) writeTime() {
-	if ln.err != nil || ln.time == nil || len(ln.time) == 0 {
-		return
+// flush encodes the entry into the line buffer then sends it to w with a single
+// Write, so a line is never split up (i.e. across UDP packets or O_APPEND writes)
+func (ln *line) flush(w io.Writer, e *Entry) error {
+	ln.buf.Reset()
+	if err := ln.enc.Encode(&ln.buf, e); err != nil {
+		return err // nothing has been written
 	}
-	_, ln.err = ln.out.w.Write(append(ln.time, ' '))
+	_, err := w.Write(ln.buf.Bytes())
+	return err
 }
 
-func (ln *line) writeColor() {
-	if ln.err != nil || ln.color == nil || len(ln.color) == 0 {
-		return
+// maxLineBuffer is the largest line buffer that is put back in the pool
+const maxLineBuffer = 64 << 10
+
+// free resets the line and puts it back in the pool
+func (ln *line) free() {
+	ln.flags = 0
+	ln.depth = 0
+	ln.level = 0
+	ln.time = nil
+	ln.prefixLevel = nil
+	ln.labeled = false
+	ln.format = ""
+	ln.kv = ln.kv[:0]
+	for i := range ln.places {
+		ln.places[i] = KeyVal{} // don't hold on to the values
 	}
-	_, ln.err = ln.out.cw.Write(ln.color)
-}
+	ln.places = ln.places[:0]
+	ln.typed = nil
+	ln.entry = Entry{}
+	ln.enc = nil
+	ln.out.dw.w = nil
+	ln.err = nil
 
-func (ln *line) writeColorEnd() {
-	if ln.err != nil || ln.color == nil || len(ln.color) == 0 {
-		return
+	if ln.buf.Cap() > maxLineBuffer {
+		ln.buf = bytes.Buffer{} // don't keep a huge line around in the pool
 	}
-	_, ln.err = ln.out.cw.Write([]byte{0x1b, '[', '0', 'm'})
+
+	lPool.Put(ln)
 }
 
-func (ln *line) writeFilename() {
-	if ln.err != nil {
-		return
+func (ln *line) caller() string {
+	if !hasFlag(ln.flags, Llongfile, Lshortfile, Lrelfile, Lfuncname, Lpackage) {
+		return ""
 	}
 
-	if hasFlag(ln.flags, Llongfile, Lshortfile) {
-		_, file, line, ok := runtime.Caller(ln.depth)
-		if !ok {
-			file, line = "???", 0
-		}
-		if hasFlag(ln.flags, Lshortfile) {
-			file = filepath.Base(file)
-		}
+	var frame, ok = ln.frame()
+	var file, line = frame.File, frame.Line
+	if !ok {
+		file, line = "???", 0
+	}
+	if !hasFlag(ln.flags, Lfuncname, Lpackage) {
+		return ln.filename(ok, file, line) // the std log flags only
+	}
 
-		fmt.Fprintf(ln.out.w, "%s:%d ", file, line)
+	var pkg, name string
+	if ok && frame.Function != "" {
+		pkg, name = splitFuncName(frame.Function)
 	}
-}
 
-func (ln *line) writePrefixLevel() {
-	if ln.err != nil || ln.prefixLevel == nil {
-		return
+	var parts = make([]string, 0, 3)
+	if hasFlag(ln.flags, Lpackage) && pkg != "" {
+		parts = append(parts, pkg)
+	}
+	if hasFlag(ln.flags, Llongfile, Lshortfile, Lrelfile) {
+		parts = append(parts, ln.filename(ok, file, line))
 	}
-	_, ln.err = ln.out.w.Write(append(ln.prefixLevel, ' '))
+	if hasFlag(ln.flags, Lfuncname) && name != "" {
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, " ")
 }
 
-func (ln *line) writePrefixUser() {
-	if ln.err != nil || ln.prefixUser == nil {
-		return
+// frame returns the code that called the logger, that is the first frame outside of this
+// package (the tests of this package count as outside). For Output it's the frame at the
+// runtime.Caller depth from caller, as it always has been.
+func (ln *line) frame() (runtime.Frame, bool) {
+	var pcs [maxCallers]uintptr
+	if ln.depth >= 0 {
+		if runtime.Callers(ln.depth+3, pcs[:1]) == 0 { // skip runtime.Callers, frame and caller
+			return runtime.Frame{}, false
+		}
+		frame, _ := runtime.CallersFrames(pcs[:1]).Next()
+		return frame, true
+	}
+
+	var frames = runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, "runtime.") {
+			return runtime.Frame{}, false // i.e. the top of a NewLog goroutine
+		}
+		if !internal(frame.File) {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
 	}
-	_, ln.err = ln.out.w.Write(append(ln.prefixUser, ' '))
 }
 
-func (ln *line) writePrint() {
-	if ln.err != nil {
-		return
+func (ln *line) filename(ok bool, file string, line int) string {
+	switch {
+	case !ok:
+	case hasFlag(ln.flags, Lrelfile):
+		file = relFile(file)
+	case hasFlag(ln.flags, Lshortfile):
+		file = filepath.Base(file)
 	}
+	return file + ":" + strconv.Itoa(line)
+}
+
+func (ln *line) message() string {
+	ln.msg.Reset()
 
 	switch ln.do {
+	case bMsg:
+		return ln.format
 	case bPrint:
-		_, ln.err = fmt.Fprint(ln.out.w, ln.v...)
+		_, ln.err = fmt.Fprint(&ln.msg, ln.v...)
 	case bPrintf:
-		_, ln.err = fmt.Fprintf(ln.out.w, ln.format, ln.v...)
+		_, ln.err = fmt.Fprintf(&ln.msg, ln.format, ln.v...)
 	case bPrintln:
-		ln.out.dw.w = ln.out.w // this reduces an allocation we must add the writer each time...
+		ln.out.dw.w = &ln.msg // this reduces an allocation we must add the writer each time...
 		_, ln.err = fmt.Fprintln(ln.out.dw, ln.v...)
+	case bPrintt:
+		ln.template()
 	}
-}
 
-func (ln *line) writeKVPairs() {
-	if ln.err != nil || len(ln.kv) == 0 {
-		return
-	}
-
-	_, ln.err = fmt.Fprint(ln.out.w, " "+ln.kv)
+	return ln.msg.String()
 }
 
-func (ln *line) writeNewLine() {
-	if ln.err != nil {
-		return
-	}
-	_, ln.err = fmt.Fprint(ln.out.w, "\n")
+// sortKV sorts the K/V pairs by key, so the output is stable
+func sortKV(kvs []KeyVal) {
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
 }
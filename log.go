@@ -7,23 +7,76 @@ import (
 	stdlog "log"
 	"regexp"
 	"sync"
+	"sync/atomic"
 )
 
 type rxMap map[*regexp.Regexp]func(Logger, map[string]string)
 
 type syncWriter struct {
-	w    io.Writer
+	w    *io.PipeWriter
 	sync *sync.WaitGroup
+	done chan struct{} // closed when the scanner goroutine returns
+
+	m       sync.Mutex // keeps the writes and close's in sync
+	partial bool       // the last write didn't end with a newline, so the scanner has a line pending
+	close   io.Closer  // closed after the scanner goroutine, for NewLog
+	refs    int32      // the loggers that write to a FilterWriter scanner, see retain and release
+}
+
+func newSyncWriter(pw *io.PipeWriter) *syncWriter {
+	return &syncWriter{w: pw, sync: new(sync.WaitGroup), done: make(chan struct{}), refs: 1}
 }
 
 func (sw *syncWriter) Write(p []byte) (n int, err error) {
-	sw.sync.Add(bytes.Count(p, []byte("\n")))
+	sw.m.Lock()
+	defer sw.m.Unlock()
+
+	var lines = bytes.Count(p, []byte("\n"))
+	sw.sync.Add(lines)
 	defer sw.sync.Wait()
 
 	n, err = sw.w.Write(p)
+	if n == 0 && err != nil {
+		sw.sync.Add(-lines) // nothing was written (i.e. after a Close), so there is nothing to wait for
+		return
+	}
+	if len(p) > 0 {
+		sw.partial = p[len(p)-1] != '\n'
+	}
 	return
 }
 
+// Close stops the scanner goroutine once it has finished with all of the writes
+func (sw *syncWriter) Close() error {
+	sw.m.Lock()
+	defer sw.m.Unlock()
+
+	if sw.partial {
+		sw.sync.Add(1) // the scanner passes on the pending line once the pipe is closed
+		sw.partial = false
+	}
+	err := sw.w.Close()
+	<-sw.done
+	if sw.close != nil {
+		if cerr := sw.close.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		sw.close = nil
+	}
+	return err
+}
+
+// retain adds a logger that writes to the scanner (i.e. from With)
+func (sw *syncWriter) retain() { atomic.AddInt32(&sw.refs, 1) }
+
+// release removes a logger that writes to the scanner, the last one stops it
+func (sw *syncWriter) release() error {
+	if atomic.AddInt32(&sw.refs, -1) == 0 {
+		return sw.Close()
+	}
+	return nil
+}
+
 func byteCounter(sw *syncWriter) (func([]byte, bool) (int, []byte, error), func()) {
 	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
 		advance, token, err = bufio.ScanLines(data, atEOF)
@@ -31,14 +84,25 @@ func byteCounter(sw *syncWriter) (func([]byte, bool) (int, []byte, error), func(
 	}, func() { sw.sync.Done() }
 }
 
+// CloseLog stops the goroutine of a std log Logger from NewLog, then closes the Logger that
+// it logs to (see Logger.Close). It does nothing for any other std log Logger.
+func CloseLog(l *stdlog.Logger) error {
+	if sw, ok := l.Writer().(*syncWriter); ok {
+		return sw.Close()
+	}
+	return nil
+}
+
 func NewLog(rxm rxMap, opts ...optFunc) *stdlog.Logger {
 	logger := New(opts...)
 
 	pr, pw := io.Pipe()
-	sw := &syncWriter{w: pw, sync: new(sync.WaitGroup)}
+	sw := newSyncWriter(pw)
+	sw.close = logger
 	split, waitForPrintToFinishBeforeTheNextScan := byteCounter(sw)
 
 	go func() {
+		defer close(sw.done)
 		scan := bufio.NewScanner(pr)
 		scan.Split(split)
 
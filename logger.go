@@ -3,8 +3,10 @@ package logger
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"io"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -21,16 +23,21 @@ var mPool = sync.Pool{
 
 var lPool = sync.Pool{
 	New: func() interface{} {
-		return &line{}
+		return &line{ts: make([]byte, 0, 64)}
 	},
 }
 
 type baseLogger struct {
 	flags    int
-	depth    int
 	display  int
 	suppress int
 
+	threshold logLevel // the least severe level that is logged, zero logs all levels
+
+	labels *levelLabels // the user-defined level labels, nil for the display style
+
+	colors map[logLevel]colorize // the level colors from WithLevelColors
+
 	ts struct {
 		now   time.Time
 		fns   []func(string) string // for ultimate formatting functions
@@ -45,9 +52,15 @@ type baseLogger struct {
 
 	kv struct {
 		set     map[string]interface{}
+		order   []string // the keys of set in the order they were added
+		ordered bool
 		marshal func(v interface{}) ([]byte, error)
+		append  *kv.Appender // set when marshal is a kv func, so Event fields can skip it
 	}
 
+	enc    Encoder
+	layout layout // the layout for the default encoder, nil for the DefaultLayout
+
 	http struct {
 		headers  KVMap
 		formatFn HTTPLogFormatFunc
@@ -58,7 +71,10 @@ type baseLogger struct {
 
 		w     io.Writer
 		cw    io.Writer
-		close []io.Closer
+		nw    io.Writer
+		close *closers      // the writers this logger added, not the ones from the logger it's With
+		scans []*syncWriter // the FilterWriter scanners of the writers, shared with the loggers from With
+		hup   io.Closer     // stops the SIGHUP handler
 	}
 
 	exit struct {
@@ -68,10 +84,7 @@ type baseLogger struct {
 	}
 
 	sync struct {
-		fw *sync.WaitGroup
 		ln *sync.Mutex
-
-		fwCnt int
 	}
 }
 
@@ -80,10 +93,12 @@ func New(opts ...optFunc) Logger {
 
 	b.ts.stamp = defaultTS
 	b.kv.marshal = kv.Marshal
+	b.kv.append = kv.PlainAppender
 	b.kv.set = make(map[string]interface{})
 	b.exit.Int = 1
 	b.exit.Func = os.Exit
 	b.sync.ln = new(sync.Mutex)
+	b.out.close = new(closers)
 
 	b.writers([]io.Writer{os.Stdout})
 
@@ -98,9 +113,10 @@ func (b *baseLogger) Flags() int {
 	return b.flags
 }
 
+// Output logs s with the caller found by runtime.Caller(calldepth) from within the
+// logger, when any of the caller flags are set
 func (b *baseLogger) Output(calldepth int, s string) error {
-	b.depth = calldepth
-	return b.print(bPrint, []interface{}{s})
+	return b.print(bPrint, []interface{}{s}, depthize(calldepth))
 }
 
 func (b *baseLogger) Prefix() string { return string(b.prefix.user) }
@@ -127,7 +143,14 @@ func (b *baseLogger) SetFlags(f int) {
 	}
 }
 
-func (b *baseLogger) SetOutput(w io.Writer) { b.writers([]io.Writer{w}) }
+// SetOutput replaces the writers, any FilterWriter goroutines of the replaced writers are stopped
+// when no other logger (from With) uses them. The replaced writers are still closed by Close.
+func (b *baseLogger) SetOutput(w io.Writer) {
+	b.sync.ln.Lock()
+	defer b.sync.ln.Unlock()
+
+	b.writers([]io.Writer{w})
+}
 
 func (b *baseLogger) SetPrefix(s string) { b.prefix.user = []byte(s) }
 
@@ -136,81 +159,289 @@ func (b *baseLogger) Writer() io.Writer { return b.out.w }
 func (b *baseLogger) FatalInt(i int) Logger { b.exit.Int = i; return b }
 
 func (b *baseLogger) Field(key string, value interface{}) Logger {
-	b.kv.set[key] = value
+	b.setField(key, value)
 	return b
 }
 
+// Fields adds all of the kvs, which are added in key order when using ordered fields
 func (b *baseLogger) Fields(kvs map[string]interface{}) Logger {
-	for key, value := range kvs {
-		b.kv.set[key] = value
+	var keys = make([]string, 0, len(kvs))
+	for key := range kvs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		b.setField(key, kvs[key])
 	}
 	return b
 }
 
+func (b *baseLogger) setField(key string, value interface{}) {
+	if _, ok := b.kv.set[key]; !ok {
+		b.kv.order = append(b.kv.order, key)
+	}
+	b.kv.set[key] = value
+}
+
+// Log logs at any level, including a level from RegisterLevel
+func (b *baseLogger) Log(level logLevel, v ...interface{}) {
+	if b.Enabled(level) {
+		b.print(bPrint, v, b.leveled(level)...)
+		b.exited(level)
+	}
+}
+
+// Logf logs the format at any level, including a level from RegisterLevel
+func (b *baseLogger) Logf(level logLevel, f string, v ...interface{}) {
+	if b.Enabled(level) {
+		b.print(bPrintf, v, append(b.leveled(level), formatize(f))...)
+		b.exited(level)
+	}
+}
+
+// Logln logs at any level, including a level from RegisterLevel
+func (b *baseLogger) Logln(level logLevel, v ...interface{}) {
+	if b.Enabled(level) {
+		b.print(bPrintln, v, b.leveled(level)...)
+		b.exited(level)
+	}
+}
+
+// Logt logs the template at any level, including a level from RegisterLevel
+func (b *baseLogger) Logt(level logLevel, t string, v ...interface{}) {
+	if b.Enabled(level) {
+		b.print(bPrintt, v, append(b.leveled(level), formatize(t))...)
+		b.exited(level)
+	}
+}
+
+// leveled returns the settings that the generated functions use for a level
+func (b *baseLogger) leveled(level logLevel) []setize {
+	var settings = []setize{level, b.levelize(level), b.colorize(level)}
+	if level == Panic {
+		b.exit.buf = new(bytes.Buffer)
+		settings = append(settings, writeize{b.exit.buf})
+	}
+	return settings
+}
+
+// levelize returns the label for the level, using any user-defined labels
+func (b *baseLogger) levelize(level logLevel) levelize {
+	if b.labels != nil {
+		return b.labels.label(level)
+	}
+	return level.levelize(b.display)
+}
+
+// colorize returns the color for the level, using any colors from WithLevelColors
+func (b *baseLogger) colorize(level logLevel) colorize {
+	if c, ok := b.colors[level]; ok {
+		return c
+	}
+	return level.colorize()
+}
+
+// exited does what the generated Fatal and Panic functions do after logging
+func (b *baseLogger) exited(level logLevel) {
+	switch level {
+	case Fatal:
+		b.exit.Func(b.exit.Int)
+	case Panic:
+		panic(b.exit.buf.String())
+	}
+}
+
 func (b *baseLogger) OnErr(err error) OnErrLogger { return &onErrLogger{b: b, err: err} }
 
+// Suppress stops the levels from being logged, it adds to any levels that are
+// already suppressed. i.e. Suppress(Trace).Suppress(Debug) suppresses both
 func (b *baseLogger) Suppress(i logLevel) Logger {
-	b.suppress = int(i)
+	b.suppress |= int(i)
+	return b
+}
+
+// Unsuppress logs the levels again after they have been suppressed
+func (b *baseLogger) Unsuppress(i logLevel) Logger {
+	b.suppress &^= int(i)
+	return b
+}
+
+// Reopen reopens all of the writers that are (or wrap) a Reopener (i.e. a FileWriter), returning the
+// first error. No lines are written while the writers are being reopened.
+func (b *baseLogger) Reopen() (err error) {
+	b.sync.ln.Lock()
+	defer b.sync.ln.Unlock()
+
+	for _, w := range b.out.raw {
+		if r, ok := reopener(w); ok {
+			if rerr := r.Reopen(); rerr != nil && err == nil {
+				err = rerr
+			}
+		}
+	}
+	return err
+}
+
+// reopener returns w, or the writer that w wraps, as a Reopener
+func reopener(w io.Writer) (Reopener, bool) {
+	for {
+		if r, ok := w.(Reopener); ok {
+			return r, true
+		}
+		u, ok := w.(unwrapper)
+		if !ok {
+			return nil, false
+		}
+		w = u.unwrap()
+	}
+}
+
+// syncTimeout is the longest Sync waits for a BufferedWriter to flush, as the logging waits on Sync
+var syncTimeout = 5 * time.Second
+
+// Sync flushes the writers that are (or wrap) a BufferedWriter (i.e. an AsyncWriter), and commits
+// any FileWriter files to disk, returning the first error. A *os.File is left to the caller. A
+// flush that takes longer than 5 seconds returns context.DeadlineExceeded, so a stuck writer
+// doesn't hold up the logging forever.
+func (b *baseLogger) Sync() (err error) {
+	b.sync.ln.Lock()
+	defer b.sync.ln.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+
+	var set = func(e error) {
+		if e != nil && err == nil {
+			err = e
+		}
+	}
+	for _, w := range b.out.raw {
+		for w != nil {
+			if _, ok := w.(*os.File); ok {
+				break
+			}
+			if f, ok := w.(BufferedWriter); ok {
+				set(f.Flush(ctx))
+			}
+			if s, ok := w.(interface{ Sync() error }); ok {
+				set(s.Sync())
+			}
+			u, ok := w.(unwrapper)
+			if !ok {
+				break
+			}
+			w = u.unwrap()
+		}
+	}
+	return err
+}
+
+// Close closes all of the writers the logger added, that is any io.Closer writer (i.e. a
+// FileWriter, NetWriter or AsyncWriter, which writes everything it has queued) except for a
+// *os.File. It also stops any SIGHUP handler, and the FilterWriter goroutines when no other
+// logger uses them. A logger from With only closes the writers from its own options, so the
+// writers it shares with its parent are left open. It's safe to call Close more than once and
+// from any goroutine (i.e. a signal handler).
+func (b *baseLogger) Close() (err error) {
+	b.sync.ln.Lock()
+	defer b.sync.ln.Unlock()
+
+	err = b.release(b.out.scans)
+	b.out.scans = nil
+	if cerr := b.out.close.close(); cerr != nil && err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// release lets go of the scanners, stopping any that are no longer used, and returns the first error
+func (b *baseLogger) release(scans []*syncWriter) (err error) {
+	for _, sw := range scans {
+		if rerr := sw.release(); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// SetLevel only logs the levels that are at least as severe as level (i.e. SetLevel(Warn)
+// logs Warn, Error, Fatal and Panic). Any suppressed levels are still not logged.
+func (b *baseLogger) SetLevel(level logLevel) Logger {
+	b.threshold = level
 	return b
 }
 
+// Enabled returns true when the level will be logged, so that expensive
+// work for a log line can be skipped when it won't be logged
+func (b *baseLogger) Enabled(level logLevel) bool {
+	return !hasFlag(b.suppress, level.flag()) && level.severity() >= b.threshold.severity()
+}
+
 func (b *baseLogger) With(opts ...optFunc) Logger {
 	bb := duplicate(b)
+
+	// the fields are copied, so a Field from bb or b is only added to that logger
+	bb.kv.set = make(map[string]interface{}, len(b.kv.set))
+	for key, value := range b.kv.set {
+		bb.kv.set[key] = value
+	}
+	bb.kv.order = append([]string(nil), b.kv.order...)
+
+	// the writers from b are still b's to close, and the scanners are shared. The SIGHUP
+	// handler is b's too, so bb can have its own with WithReopenOnHUP
+	bb.out.close = new(closers)
+	bb.out.hup = nil
+	for _, sw := range bb.out.scans {
+		sw.retain()
+	}
 	for _, opt := range opts {
 		opt(bb)
 	}
 	return bb
 }
 
-func (*baseLogger) scan(fn func(string)) io.Writer {
+// scan passes each line written to the returned writer to fn. A write
+// does not return until fn has been called for every line in it.
+// The goroutine is stopped once it's released by every logger.
+func (b *baseLogger) scan(fn func(string)) io.Writer {
 	pr, pw := io.Pipe()
+	sw := newSyncWriter(pw)
 	go func() {
+		defer close(sw.done)
 		scan := bufio.NewScanner(pr)
 		for scan.Scan() {
 			fn(scan.Text())
+			sw.sync.Done()
 		}
 	}()
-	return pw
+	b.out.scans = append(b.out.scans, sw)
+	return sw
 }
 
-func (b *baseLogger) time() []byte {
-	if b.ts.text != nil {
-		return b.ts.text
-	}
-
-	if b.ts.stamp == "" {
-		return nil
-	}
-
+func (b *baseLogger) now() time.Time {
 	var now = b.ts.now
 	if now.IsZero() {
 		now = time.Now()
 	}
 
 	if (b.flags & LUTC) != 0 {
-		now = b.ts.now.UTC()
+		now = now.UTC()
 	}
+	return now
+}
 
-	var (
-		r    rune
-		n, i int
-		ts   = make([]byte, 0, 20)
-	)
-
-	for i, r = range b.ts.stamp {
-		if tsFormat, ok := tsRuneMap[r]; ok {
-			if i > n {
-				ts = append(ts, []byte(b.ts.stamp[n:i])...)
-			}
-			ts = now.AppendFormat(ts, tsFormat)
-			n = i + 1
-			continue
-		}
+// time appends the formatted timestamp to dst, unless there is a timestamp text to use
+func (b *baseLogger) time(dst []byte, now time.Time) []byte {
+	if b.ts.text != nil {
+		return b.ts.text
 	}
-	if i >= n {
-		ts = append(ts, []byte(b.ts.stamp[n:len(b.ts.stamp)])...)
+
+	if b.ts.stamp == "" {
+		return nil
 	}
 
+	var ts = appendStamp(dst, b.ts.stamp, now)
+
 	if len(b.ts.fns) > 0 {
 		s := string(ts)
 		for _, fn := range b.ts.fns {
@@ -222,137 +453,278 @@ func (b *baseLogger) time() []byte {
 	return ts
 }
 
-func (b *baseLogger) filter(v []interface{}) (_ []interface{}, kv string, err error) {
+func (b *baseLogger) filter(v []interface{}, kvs []KeyVal) (_ []interface{}, _ []KeyVal) {
+	if b.kv.ordered {
+		return b.filterOrdered(v, kvs)
+	}
+
 	var m = mPool.Get().(map[string]interface{})
 
 	for k, v := range b.kv.set {
-		m[k] = v
+		m[k] = resolve(v)
 	}
 
 	fv := v[:0]
 	for _, i := range v {
 		if p, ok := i.(KeyVal); ok {
-			m[string(p.Key)] = p.Value
+			m[string(p.Key)] = resolve(p.Value)
 			continue
 		}
 		if p, ok := i.(KVMap); ok {
 			for key, value := range p {
-				m[string(key)] = value
+				m[string(key)] = resolve(value)
 			}
 			continue
 		}
 		if p, ok := i.(map[K]V); ok {
 			for key, value := range p {
-				m[string(key)] = value
+				m[string(key)] = resolve(value)
 			}
 			continue
 		}
-		fv = append(fv, i)
+		fv = append(fv, resolve(i))
 	}
 
-	if m != nil && len(m) > 0 {
-		_kv, err := b.kv.marshal(m)
-		if err != nil {
-			return nil, "", err
-		}
-		kv = string(_kv)
-		for k := range m {
+	if len(m) > 0 {
+		for k, v := range m {
+			kvs = append(kvs, KeyVal{K(k), v})
 			delete(m, k)
 		}
+		sortKV(kvs)
 	}
 
 	mPool.Put(m)
 
-	return fv, kv, nil
+	return fv, kvs
+}
+
+// filterOrdered is the same as filter, but the K/V pairs are kept in the order
+// they were added. The Field(s) values come first, followed by the K/V values in
+// the order they were passed in. When a key is used more than once, the last value
+// wins and the key keeps the place where it was first added.
+func (b *baseLogger) filterOrdered(v []interface{}, kvs []KeyVal) (_ []interface{}, _ []KeyVal) {
+	for _, k := range b.kv.order {
+		if value, ok := b.kv.set[k]; ok {
+			kvs = addKV(kvs, K(k), resolve(value))
+		}
+	}
+
+	fv := v[:0]
+	for _, i := range v {
+		if p, ok := i.(KeyVal); ok {
+			kvs = addKV(kvs, p.Key, resolve(p.Value))
+			continue
+		}
+		if p, ok := i.(KVMap); ok {
+			kvs = addKVMap(kvs, p)
+			continue
+		}
+		if p, ok := i.(map[K]V); ok {
+			kvs = addKVMap(kvs, p)
+			continue
+		}
+		fv = append(fv, resolve(i))
+	}
+
+	return fv, kvs
+}
+
+// addKV adds the key and value, replacing the value of a key that has already been added
+func addKV(kvs []KeyVal, key K, value V) []KeyVal {
+	for i := range kvs {
+		if kvs[i].Key == key {
+			kvs[i].Value = value
+			return kvs
+		}
+	}
+	return append(kvs, KeyVal{key, value})
+}
+
+// addKVMap adds the values of a map, these are added in key order
+// because there is no other order to use
+func addKVMap(kvs []KeyVal, m map[K]V) []KeyVal {
+	var n = len(kvs)
+	for key, value := range m {
+		kvs = addKV(kvs, key, resolve(value))
+	}
+	sortKV(kvs[n:])
+	return kvs
+}
+
+// maxResolve is the number of times a LogValuer can return another
+// LogValuer before the value is used as is
+const maxResolve = 8
+
+// resolve returns the value of a LogValuer
+func resolve(v interface{}) interface{} {
+	for i := 0; i < maxResolve; i++ {
+		lv, ok := v.(LogValuer)
+		if !ok {
+			break
+		}
+		v = lv.LogValue()
+	}
+	return v
 }
 
 func (b *baseLogger) writers(ws []io.Writer) {
+	var replaced = b.out.scans
+	b.out.scans = nil
+	defer b.release(replaced) // once the new writers are in place
+
 	cws := make([]io.Writer, 0, len(ws))
+	nws := make([]io.Writer, 0, len(ws))
 	fws := make([]*filterWriter, 0, len(ws))
 
-	ow := ws[:0]
 	for _, w := range ws {
-		if _, ok := w.(NoColorWriter); !ok {
-			cws = append(cws, w)
+		if fw, ok := w.(*filterWriter); ok {
+			w = fw.w
+		}
+		if c, ok := closer(w); ok {
+			b.out.close.add(c)
 		}
+	}
+
+	ow := make([]io.Writer, 0, len(ws)) // not ws[:0], the raw writers are kept as they are
+	for _, w := range ws {
 		if fw, ok := w.(*filterWriter); ok {
 			fws = append(fws, fw)
 			continue
 		}
+		if colorable(w) {
+			cws = append(cws, w)
+		} else {
+			nws = append(nws, w)
+		}
 		ow = append(ow, w)
 	}
 
-	b.sync.fw = new(sync.WaitGroup)
-	b.sync.fwCnt = len(fws)
 	for _, fw := range fws {
+		fw := fw
 		w := b.scan(func(text string) {
 			for _, filter := range fw.filters {
-				if !filter.Check(text) {
-					fw.Write(append([]byte(text), '\n'))
+				if filter.Check(text) {
+					return
 				}
-				b.sync.fw.Done()
 			}
+			fw.Write(append([]byte(text), '\n'))
 		})
 
+		if colorable(fw.w) {
+			cws = append(cws, w)
+		} else {
+			nws = append(nws, w)
+		}
 		ow = append(ow, w)
 	}
 
 	b.out.raw = ws
+	b.out.cw, b.out.nw = multiWriter(cws), multiWriter(nws)
 
-	if len(ws) == 1 {
+	if len(ws) == 1 && len(fws) == 0 {
 		b.out.w = ws[0]
-		if _, ok := ws[0].(NoColorWriter); !ok {
-			b.out.cw = ws[0]
-		}
 		return
 	}
 
 	b.out.w = io.MultiWriter(ow...)
-	b.out.cw = io.MultiWriter(cws...)
 }
 
-func (b *baseLogger) print(prnt printKind, v []interface{}, settings ...setize) (err error) {
-	var ln = lPool.Get().(*line)
-	defer func() {
-
-		ln.flags = 0
-		ln.depth = 0
-		ln.time = nil
-		ln.prefixLevel = nil
-		ln.format = ""
-		ln.kv = ""
-		ln.out.dw.w = nil
-		ln.err = nil
-
-		lPool.Put(ln)
-	}()
+// closers are the writers (and the SIGHUP handler) that are closed by Close
+type closers struct {
+	m    sync.Mutex
+	list []io.Closer
+}
 
-	ln.do = prnt
-	ln.flags = b.flags
-	ln.depth = b.depth
-	ln.time = b.time()
-	ln.color = b.color
-	ln.prefixUser = b.prefix.user
-	if ln.out.dw == nil {
-		ln.out.dw = &dropCRWriter{}
+func (cs *closers) add(c io.Closer) {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+	cs.list = append(cs.list, c)
+}
+
+// close closes everything in the reverse order it was added, and returns the first error
+func (cs *closers) close() (err error) {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+
+	for i := len(cs.list) - 1; i >= 0; i-- {
+		if cerr := cs.list[i].Close(); cerr != nil && err == nil {
+			err = cerr
+		}
 	}
+	cs.list = nil
+	return err
+}
 
-	ln.out.w = b.out.w
-	ln.out.cw = b.out.cw
+// closer returns the writer as an io.Closer when the logger should close it, that is
+// any closer (i.e. a FileWriter or NetWriter) except a *os.File, which is left to the caller
+func closer(w io.Writer) (io.Closer, bool) {
+	if _, ok := w.(*os.File); ok {
+		return nil, false
+	}
+	c, ok := w.(io.Closer)
+	return c, ok
+}
+
+// multiWriter returns nil when there are no writers, otherwise it
+// skips wrapping a single writer
+func multiWriter(ws []io.Writer) io.Writer {
+	switch len(ws) {
+	case 0:
+		return nil
+	case 1:
+		return ws[0]
+	}
+	return io.MultiWriter(ws...)
+}
+
+func (b *baseLogger) print(prnt printKind, v []interface{}, settings ...setize) (err error) {
+	var ln = b.newLine(prnt)
+	defer ln.free()
 
 	for _, s := range settings {
 		s.set(ln)
 	}
 
-	if ln.v, ln.kv, err = b.filter(v); err != nil {
-		return err
+	if prnt == bPrintt {
+		v, ln.places = templatize(ln.format, v, ln.places[:0])
 	}
 
+	ln.v, ln.kv = b.filter(v, ln.kv)
+
 	b.sync.ln.Lock()
 	defer b.sync.ln.Unlock()
 
-	b.sync.fw.Add(b.sync.fwCnt) // wait for filters... otherwise a race condition
-	defer b.sync.fw.Wait()
-
 	return ln.write()
 }
+
+// newLine returns a line from the pool that is set up with the logger values
+func (b *baseLogger) newLine(prnt printKind) *line {
+	var ln = lPool.Get().(*line)
+
+	ln.do = prnt
+	ln.flags = b.flags
+	ln.depth = -1 // find the caller outside of the logger
+	ln.now = b.now()
+	ln.time = b.time(ln.ts[:0], ln.now)
+	ln.color = b.color
+	ln.prefixUser = b.prefix.user
+	ln.labeled = b.labels != nil
+	if ln.out.dw == nil {
+		ln.out.dw = &dropCRWriter{}
+	}
+
+	ln.enc = b.enc
+	if ln.enc == nil {
+		ln.text.marshal = b.kv.marshal
+		ln.text.append = b.kv.append
+		ln.text.ordered = b.kv.ordered
+		ln.text.layout = b.layout
+		ln.enc = &ln.text
+	}
+
+	ln.out.w = b.out.w
+	ln.out.cw = b.out.cw
+	ln.out.nw = b.out.nw
+
+	return ln
+}
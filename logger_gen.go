@@ -1,6 +1,6 @@
 // GENERATED BY ./gen/main.go; DO NOT EDIT THIS FILE
 // ~~ This file is not generated by hand ~~
-// ~~ generated on: 2020-02-22 04:38:58.6751266 +0000 UTC m=+0.028521401 ~~
+// ~~ generated on: 2026-10-17 20:07:03.946708058 +0000 UTC m=+0.003035858 ~~
 package logger
 
 import (
@@ -47,62 +47,158 @@ var tsRuneMap = map[rune]string{
 	0x1f: "Monday",
 }
 
-var llMap = map[int]map[logLevel]string{
+var llMap = map[int]map[logLevel]levelize{
 	0: {
-		Info:  "INFO:",
-		Warn:  "WARN:",
-		Debug: "DEBUG:",
-		Error: "ERROR:",
-		Trace: "TRACE:",
-		Fatal: "FATAL:",
-		Panic: "PANIC:",
+		Info:  levelize("INFO:"),
+		Warn:  levelize("WARN:"),
+		Debug: levelize("DEBUG:"),
+		Error: levelize("ERROR:"),
+		Trace: levelize("TRACE:"),
+		Fatal: levelize("FATAL:"),
+		Panic: levelize("PANIC:"),
+
+		Notice:    levelize("NOTICE:"),
+		Critical:  levelize("CRITICAL:"),
+		Alert:     levelize("ALERT:"),
+		Emergency: levelize("EMERGENCY:"),
 	},
 	1: {
-		Info:  "[INFO]",
-		Warn:  "[WARN]",
-		Debug: "[DEBUG]",
-		Error: "[ERROR]",
-		Trace: "[TRACE]",
-		Fatal: "[FATAL]",
-		Panic: "[PANIC]",
+		Info:  levelize("[INFO]"),
+		Warn:  levelize("[WARN]"),
+		Debug: levelize("[DEBUG]"),
+		Error: levelize("[ERROR]"),
+		Trace: levelize("[TRACE]"),
+		Fatal: levelize("[FATAL]"),
+		Panic: levelize("[PANIC]"),
+
+		Notice:    levelize("[NOTICE]"),
+		Critical:  levelize("[CRITICAL]"),
+		Alert:     levelize("[ALERT]"),
+		Emergency: levelize("[EMERGENCY]"),
 	},
 	2: {
-		Info:  "INF:",
-		Warn:  "WRN:",
-		Debug: "DBG:",
-		Error: "ERR:",
-		Trace: "TRC:",
-		Fatal: "FAT:",
-		Panic: "PAN:",
+		Info:  levelize("INF:"),
+		Warn:  levelize("WRN:"),
+		Debug: levelize("DBG:"),
+		Error: levelize("ERR:"),
+		Trace: levelize("TRC:"),
+		Fatal: levelize("FAT:"),
+		Panic: levelize("PAN:"),
+
+		Notice:    levelize("NTC:"),
+		Critical:  levelize("CRT:"),
+		Alert:     levelize("ALR:"),
+		Emergency: levelize("EMR:"),
 	},
 	3: {
-		Info:  "[INF]",
-		Warn:  "[WRN]",
-		Debug: "[DBG]",
-		Error: "[ERR]",
-		Trace: "[TRC]",
-		Fatal: "[FAT]",
-		Panic: "[PAN]",
+		Info:  levelize("[INF]"),
+		Warn:  levelize("[WRN]"),
+		Debug: levelize("[DBG]"),
+		Error: levelize("[ERR]"),
+		Trace: levelize("[TRC]"),
+		Fatal: levelize("[FAT]"),
+		Panic: levelize("[PAN]"),
+
+		Notice:    levelize("[NTC]"),
+		Critical:  levelize("[CRT]"),
+		Alert:     levelize("[ALR]"),
+		Emergency: levelize("[EMR]"),
 	},
 }
 
+var llNames = map[logLevel]string{
+	Info:      "info",
+	Warn:      "warn",
+	Debug:     "debug",
+	Error:     "error",
+	Trace:     "trace",
+	Fatal:     "fatal",
+	Panic:     "panic",
+	HTTP:      "http",
+	Notice:    "notice",
+	Critical:  "critical",
+	Alert:     "alert",
+	Emergency: "emergency",
+}
+
+var llColors = map[logLevel]colorize{
+	Info:  colorize("\x1b[32m"),
+	Warn:  colorize("\x1b[33m"),
+	Debug: colorize("\x1b[36m"),
+	Error: colorize("\x1b[35m"),
+	Trace: colorize("\x1b[34m"),
+	Fatal: colorize("\x1b[31m"),
+	Panic: colorize("\x1b[31m"),
+
+	Notice:    colorize("\x1b[37m"),
+	Critical:  colorize("\x1b[31m"),
+	Alert:     colorize("\x1b[31m"),
+	Emergency: colorize("\x1b[31m"),
+}
+
+var llSeverity = map[logLevel]int{
+	Info:      30,
+	Warn:      50,
+	Debug:     20,
+	Error:     60,
+	Trace:     10,
+	Fatal:     80,
+	Panic:     90,
+	HTTP:      30,
+	Notice:    40,
+	Critical:  70,
+	Alert:     85,
+	Emergency: 100,
+}
+
+var llSyslog = map[logLevel]int{
+	Info:      6,
+	Warn:      4,
+	Debug:     7,
+	Error:     3,
+	Trace:     7,
+	Fatal:     2,
+	Panic:     0,
+	HTTP:      6,
+	Notice:    5,
+	Critical:  2,
+	Alert:     1,
+	Emergency: 0,
+}
+
+// llNext is the first level bit that is free for RegisterLevel to use
+const llNext = Emergency << 1
+
 func (ll logLevel) flag() int { return int(ll) }
 
+// severity returns the order of the level, it is higher the more severe the level
+func (ll logLevel) severity() int {
+	if s, ok := llSeverity[ll]; ok {
+		return s
+	}
+	return ll.registered().severity
+}
+
+// String returns the lowercase name of the log level
+func (ll logLevel) String() string {
+	if s, ok := llNames[ll]; ok {
+		return s
+	}
+	return ll.registered().name
+}
+
 func (ll logLevel) levelize(display int) levelize {
-	return levelize(llMap[display][ll])
+	if l, ok := llMap[display][ll]; ok {
+		return l
+	}
+	return ll.registered().levelize(display)
 }
 
 func (ll logLevel) colorize() colorize {
-	m := map[logLevel]string{
-		Info:  "\x1b[32m",
-		Warn:  "\x1b[33m",
-		Debug: "\x1b[36m",
-		Error: "\x1b[35m",
-		Trace: "\x1b[34m",
-		Fatal: "\x1b[31m",
-		Panic: "\x1b[31m",
+	if c, ok := llColors[ll]; ok {
+		return c
 	}
-	return colorize(m[ll])
+	return ll.registered().color
 }
 
 // Helper Functions
@@ -110,13 +206,17 @@ func (ll logLevel) colorize() colorize {
 func duplicate(b *baseLogger) *baseLogger {
 	bb := new(baseLogger)
 	bb.flags = b.flags
-	bb.depth = b.depth
 	bb.display = b.display
 	bb.suppress = b.suppress
+	bb.threshold = b.threshold
+	bb.labels = b.labels
+	bb.colors = b.colors
 	bb.ts = b.ts
 	bb.color = b.color
 	bb.prefix = b.prefix
 	bb.kv = b.kv
+	bb.enc = b.enc
+	bb.layout = b.layout
 	bb.http = b.http
 	bb.out = b.out
 	bb.exit = b.exit
@@ -127,161 +227,308 @@ func duplicate(b *baseLogger) *baseLogger {
 // Standard and Extended Logger Functions
 
 func (b *baseLogger) Print(v ...interface{}) {
-	if !hasFlag(b.suppress, Info.flag()) {
+	if b.Enabled(Info) {
 		b.print(bPrint, v)
 	}
 }
 
 func (b *baseLogger) Printf(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, Info.flag()) {
+	if b.Enabled(Info) {
 		b.print(bPrintf, v, formatize(f))
 	}
 }
 
 func (b *baseLogger) Println(v ...interface{}) {
-	if !hasFlag(b.suppress, Info.flag()) {
+	if b.Enabled(Info) {
 		b.print(bPrintln, v)
 	}
 }
 
+func (b *baseLogger) Printt(t string, v ...interface{}) {
+	if b.Enabled(Info) {
+		b.print(bPrintt, v, formatize(t))
+	}
+}
+
 func (b *baseLogger) Info(v ...interface{}) {
-	if !hasFlag(b.suppress, Info.flag()) {
-		b.print(bPrint, v, Info.levelize(b.display), Info.colorize())
+	if b.Enabled(Info) {
+		b.print(bPrint, v, Info, b.levelize(Info), b.colorize(Info))
 	}
 }
 
 func (b *baseLogger) Infof(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, Info.flag()) {
-		b.print(bPrintf, v, formatize(f), Info.levelize(b.display), Info.colorize())
+	if b.Enabled(Info) {
+		b.print(bPrintf, v, formatize(f), Info, b.levelize(Info), b.colorize(Info))
 	}
 }
 
 func (b *baseLogger) Infoln(v ...interface{}) {
-	if !hasFlag(b.suppress, Info.flag()) {
-		b.print(bPrintln, v, Info.levelize(b.display), Info.colorize())
+	if b.Enabled(Info) {
+		b.print(bPrintln, v, Info, b.levelize(Info), b.colorize(Info))
+	}
+}
+
+func (b *baseLogger) Infot(t string, v ...interface{}) {
+	if b.Enabled(Info) {
+		b.print(bPrintt, v, formatize(t), Info, b.levelize(Info), b.colorize(Info))
 	}
 }
 
 func (b *baseLogger) Warn(v ...interface{}) {
-	if !hasFlag(b.suppress, Warn.flag()) {
-		b.print(bPrint, v, Warn.levelize(b.display), Warn.colorize())
+	if b.Enabled(Warn) {
+		b.print(bPrint, v, Warn, b.levelize(Warn), b.colorize(Warn))
 	}
 }
 
 func (b *baseLogger) Warnf(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, Warn.flag()) {
-		b.print(bPrintf, v, formatize(f), Warn.levelize(b.display), Warn.colorize())
+	if b.Enabled(Warn) {
+		b.print(bPrintf, v, formatize(f), Warn, b.levelize(Warn), b.colorize(Warn))
 	}
 }
 
 func (b *baseLogger) Warnln(v ...interface{}) {
-	if !hasFlag(b.suppress, Warn.flag()) {
-		b.print(bPrintln, v, Warn.levelize(b.display), Warn.colorize())
+	if b.Enabled(Warn) {
+		b.print(bPrintln, v, Warn, b.levelize(Warn), b.colorize(Warn))
+	}
+}
+
+func (b *baseLogger) Warnt(t string, v ...interface{}) {
+	if b.Enabled(Warn) {
+		b.print(bPrintt, v, formatize(t), Warn, b.levelize(Warn), b.colorize(Warn))
 	}
 }
 
 func (b *baseLogger) Debug(v ...interface{}) {
-	if !hasFlag(b.suppress, Debug.flag()) {
-		b.print(bPrint, v, Debug.levelize(b.display), Debug.colorize())
+	if b.Enabled(Debug) {
+		b.print(bPrint, v, Debug, b.levelize(Debug), b.colorize(Debug))
 	}
 }
 
 func (b *baseLogger) Debugf(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, Debug.flag()) {
-		b.print(bPrintf, v, formatize(f), Debug.levelize(b.display), Debug.colorize())
+	if b.Enabled(Debug) {
+		b.print(bPrintf, v, formatize(f), Debug, b.levelize(Debug), b.colorize(Debug))
 	}
 }
 
 func (b *baseLogger) Debugln(v ...interface{}) {
-	if !hasFlag(b.suppress, Debug.flag()) {
-		b.print(bPrintln, v, Debug.levelize(b.display), Debug.colorize())
+	if b.Enabled(Debug) {
+		b.print(bPrintln, v, Debug, b.levelize(Debug), b.colorize(Debug))
+	}
+}
+
+func (b *baseLogger) Debugt(t string, v ...interface{}) {
+	if b.Enabled(Debug) {
+		b.print(bPrintt, v, formatize(t), Debug, b.levelize(Debug), b.colorize(Debug))
 	}
 }
 
 func (b *baseLogger) Error(v ...interface{}) {
-	if !hasFlag(b.suppress, Error.flag()) {
-		b.print(bPrint, v, Error.levelize(b.display), Error.colorize())
+	if b.Enabled(Error) {
+		b.print(bPrint, v, Error, b.levelize(Error), b.colorize(Error))
 	}
 }
 
 func (b *baseLogger) Errorf(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, Error.flag()) {
-		b.print(bPrintf, v, formatize(f), Error.levelize(b.display), Error.colorize())
+	if b.Enabled(Error) {
+		b.print(bPrintf, v, formatize(f), Error, b.levelize(Error), b.colorize(Error))
 	}
 }
 
 func (b *baseLogger) Errorln(v ...interface{}) {
-	if !hasFlag(b.suppress, Error.flag()) {
-		b.print(bPrintln, v, Error.levelize(b.display), Error.colorize())
+	if b.Enabled(Error) {
+		b.print(bPrintln, v, Error, b.levelize(Error), b.colorize(Error))
+	}
+}
+
+func (b *baseLogger) Errort(t string, v ...interface{}) {
+	if b.Enabled(Error) {
+		b.print(bPrintt, v, formatize(t), Error, b.levelize(Error), b.colorize(Error))
 	}
 }
 
 func (b *baseLogger) Trace(v ...interface{}) {
-	if !hasFlag(b.suppress, Trace.flag()) {
-		b.print(bPrint, v, Trace.levelize(b.display), Trace.colorize())
+	if b.Enabled(Trace) {
+		b.print(bPrint, v, Trace, b.levelize(Trace), b.colorize(Trace))
 	}
 }
 
 func (b *baseLogger) Tracef(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, Trace.flag()) {
-		b.print(bPrintf, v, formatize(f), Trace.levelize(b.display), Trace.colorize())
+	if b.Enabled(Trace) {
+		b.print(bPrintf, v, formatize(f), Trace, b.levelize(Trace), b.colorize(Trace))
 	}
 }
 
 func (b *baseLogger) Traceln(v ...interface{}) {
-	if !hasFlag(b.suppress, Trace.flag()) {
-		b.print(bPrintln, v, Trace.levelize(b.display), Trace.colorize())
+	if b.Enabled(Trace) {
+		b.print(bPrintln, v, Trace, b.levelize(Trace), b.colorize(Trace))
+	}
+}
+
+func (b *baseLogger) Tracet(t string, v ...interface{}) {
+	if b.Enabled(Trace) {
+		b.print(bPrintt, v, formatize(t), Trace, b.levelize(Trace), b.colorize(Trace))
 	}
 }
 
 func (b *baseLogger) Fatal(v ...interface{}) {
-	if !hasFlag(b.suppress, Fatal.flag()) {
-		b.print(bPrint, v, Fatal.levelize(b.display), Fatal.colorize())
+	if b.Enabled(Fatal) {
+		b.print(bPrint, v, Fatal, b.levelize(Fatal), b.colorize(Fatal))
 		b.exit.Func(b.exit.Int)
 	}
 }
 
 func (b *baseLogger) Fatalf(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, Fatal.flag()) {
-		b.print(bPrintf, v, formatize(f), Fatal.levelize(b.display), Fatal.colorize())
+	if b.Enabled(Fatal) {
+		b.print(bPrintf, v, formatize(f), Fatal, b.levelize(Fatal), b.colorize(Fatal))
 		b.exit.Func(b.exit.Int)
 	}
 }
 
 func (b *baseLogger) Fatalln(v ...interface{}) {
-	if !hasFlag(b.suppress, Fatal.flag()) {
-		b.print(bPrintln, v, Fatal.levelize(b.display), Fatal.colorize())
+	if b.Enabled(Fatal) {
+		b.print(bPrintln, v, Fatal, b.levelize(Fatal), b.colorize(Fatal))
+		b.exit.Func(b.exit.Int)
+	}
+}
+
+func (b *baseLogger) Fatalt(t string, v ...interface{}) {
+	if b.Enabled(Fatal) {
+		b.print(bPrintt, v, formatize(t), Fatal, b.levelize(Fatal), b.colorize(Fatal))
 		b.exit.Func(b.exit.Int)
 	}
 }
 
 func (b *baseLogger) Panic(v ...interface{}) {
-	if !hasFlag(b.suppress, Panic.flag()) {
+	if b.Enabled(Panic) {
 		b.exit.buf = new(bytes.Buffer)
-		b.print(bPrint, v, writeize{b.exit.buf}, Panic.levelize(b.display), Panic.colorize())
+		b.print(bPrint, v, writeize{b.exit.buf}, Panic, b.levelize(Panic), b.colorize(Panic))
 		panic(b.exit.buf.String())
 	}
 }
 
 func (b *baseLogger) Panicf(f string, v ...interface{}) {
-	if !hasFlag(b.suppress, Panic.flag()) {
+	if b.Enabled(Panic) {
 		b.exit.buf = new(bytes.Buffer)
-		b.print(bPrintf, v, formatize(f), writeize{b.exit.buf}, Panic.levelize(b.display), Panic.colorize())
+		b.print(bPrintf, v, formatize(f), writeize{b.exit.buf}, Panic, b.levelize(Panic), b.colorize(Panic))
 		panic(b.exit.buf.String())
 	}
 }
 
 func (b *baseLogger) Panicln(v ...interface{}) {
-	if !hasFlag(b.suppress, Panic.flag()) {
+	if b.Enabled(Panic) {
 		b.exit.buf = new(bytes.Buffer)
-		b.print(bPrintln, v, writeize{b.exit.buf}, Panic.levelize(b.display), Panic.colorize())
+		b.print(bPrintln, v, writeize{b.exit.buf}, Panic, b.levelize(Panic), b.colorize(Panic))
+		panic(b.exit.buf.String())
+	}
+}
+
+func (b *baseLogger) Panict(t string, v ...interface{}) {
+	if b.Enabled(Panic) {
+		b.exit.buf = new(bytes.Buffer)
+		b.print(bPrintt, v, formatize(t), writeize{b.exit.buf}, Panic, b.levelize(Panic), b.colorize(Panic))
 		panic(b.exit.buf.String())
 	}
 }
 
 func (b *baseLogger) HTTPln(v ...interface{}) {
-	if !hasFlag(b.suppress, HTTP.flag()) {
-		b.print(bPrintln, v, timeize(nil))
+	if b.Enabled(HTTP) {
+		b.print(bPrintln, v, HTTP, timeize(nil))
+	}
+}
+
+func (b *baseLogger) Notice(v ...interface{}) {
+	if b.Enabled(Notice) {
+		b.print(bPrint, v, Notice, b.levelize(Notice), b.colorize(Notice))
+	}
+}
+
+func (b *baseLogger) Noticef(f string, v ...interface{}) {
+	if b.Enabled(Notice) {
+		b.print(bPrintf, v, formatize(f), Notice, b.levelize(Notice), b.colorize(Notice))
+	}
+}
+
+func (b *baseLogger) Noticeln(v ...interface{}) {
+	if b.Enabled(Notice) {
+		b.print(bPrintln, v, Notice, b.levelize(Notice), b.colorize(Notice))
+	}
+}
+
+func (b *baseLogger) Noticet(t string, v ...interface{}) {
+	if b.Enabled(Notice) {
+		b.print(bPrintt, v, formatize(t), Notice, b.levelize(Notice), b.colorize(Notice))
+	}
+}
+
+func (b *baseLogger) Critical(v ...interface{}) {
+	if b.Enabled(Critical) {
+		b.print(bPrint, v, Critical, b.levelize(Critical), b.colorize(Critical))
+	}
+}
+
+func (b *baseLogger) Criticalf(f string, v ...interface{}) {
+	if b.Enabled(Critical) {
+		b.print(bPrintf, v, formatize(f), Critical, b.levelize(Critical), b.colorize(Critical))
+	}
+}
+
+func (b *baseLogger) Criticalln(v ...interface{}) {
+	if b.Enabled(Critical) {
+		b.print(bPrintln, v, Critical, b.levelize(Critical), b.colorize(Critical))
+	}
+}
+
+func (b *baseLogger) Criticalt(t string, v ...interface{}) {
+	if b.Enabled(Critical) {
+		b.print(bPrintt, v, formatize(t), Critical, b.levelize(Critical), b.colorize(Critical))
+	}
+}
+
+func (b *baseLogger) Alert(v ...interface{}) {
+	if b.Enabled(Alert) {
+		b.print(bPrint, v, Alert, b.levelize(Alert), b.colorize(Alert))
+	}
+}
+
+func (b *baseLogger) Alertf(f string, v ...interface{}) {
+	if b.Enabled(Alert) {
+		b.print(bPrintf, v, formatize(f), Alert, b.levelize(Alert), b.colorize(Alert))
+	}
+}
+
+func (b *baseLogger) Alertln(v ...interface{}) {
+	if b.Enabled(Alert) {
+		b.print(bPrintln, v, Alert, b.levelize(Alert), b.colorize(Alert))
+	}
+}
+
+func (b *baseLogger) Alertt(t string, v ...interface{}) {
+	if b.Enabled(Alert) {
+		b.print(bPrintt, v, formatize(t), Alert, b.levelize(Alert), b.colorize(Alert))
+	}
+}
+
+func (b *baseLogger) Emergency(v ...interface{}) {
+	if b.Enabled(Emergency) {
+		b.print(bPrint, v, Emergency, b.levelize(Emergency), b.colorize(Emergency))
+	}
+}
+
+func (b *baseLogger) Emergencyf(f string, v ...interface{}) {
+	if b.Enabled(Emergency) {
+		b.print(bPrintf, v, formatize(f), Emergency, b.levelize(Emergency), b.colorize(Emergency))
+	}
+}
+
+func (b *baseLogger) Emergencyln(v ...interface{}) {
+	if b.Enabled(Emergency) {
+		b.print(bPrintln, v, Emergency, b.levelize(Emergency), b.colorize(Emergency))
+	}
+}
+
+func (b *baseLogger) Emergencyt(t string, v ...interface{}) {
+	if b.Enabled(Emergency) {
+		b.print(bPrintt, v, formatize(t), Emergency, b.levelize(Emergency), b.colorize(Emergency))
 	}
 }
 
@@ -311,6 +558,14 @@ func (e *onErrLogger) Println(v ...interface{}) (rtn Return) {
 	return rtn
 }
 
+func (e *onErrLogger) Printt(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Printt(t, v...)
+	}
+	return rtn
+}
+
 func (e *onErrLogger) Info(v ...interface{}) (rtn Return) {
 	rtn.err = e.err
 	if e.popOnErr(v) {
@@ -335,6 +590,14 @@ func (e *onErrLogger) Infoln(v ...interface{}) (rtn Return) {
 	return rtn
 }
 
+func (e *onErrLogger) Infot(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Infot(t, v...)
+	}
+	return rtn
+}
+
 func (e *onErrLogger) Warn(v ...interface{}) (rtn Return) {
 	rtn.err = e.err
 	if e.popOnErr(v) {
@@ -359,6 +622,14 @@ func (e *onErrLogger) Warnln(v ...interface{}) (rtn Return) {
 	return rtn
 }
 
+func (e *onErrLogger) Warnt(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Warnt(t, v...)
+	}
+	return rtn
+}
+
 func (e *onErrLogger) Debug(v ...interface{}) (rtn Return) {
 	rtn.err = e.err
 	if e.popOnErr(v) {
@@ -383,6 +654,14 @@ func (e *onErrLogger) Debugln(v ...interface{}) (rtn Return) {
 	return rtn
 }
 
+func (e *onErrLogger) Debugt(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Debugt(t, v...)
+	}
+	return rtn
+}
+
 func (e *onErrLogger) Error(v ...interface{}) (rtn Return) {
 	rtn.err = e.err
 	if e.popOnErr(v) {
@@ -407,6 +686,14 @@ func (e *onErrLogger) Errorln(v ...interface{}) (rtn Return) {
 	return rtn
 }
 
+func (e *onErrLogger) Errort(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Errort(t, v...)
+	}
+	return rtn
+}
+
 func (e *onErrLogger) Trace(v ...interface{}) (rtn Return) {
 	rtn.err = e.err
 	if e.popOnErr(v) {
@@ -431,6 +718,14 @@ func (e *onErrLogger) Traceln(v ...interface{}) (rtn Return) {
 	return rtn
 }
 
+func (e *onErrLogger) Tracet(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Tracet(t, v...)
+	}
+	return rtn
+}
+
 func (e *onErrLogger) Fatal(v ...interface{}) (rtn Return) {
 	rtn.err = e.err
 	if e.popOnErr(v) {
@@ -455,6 +750,14 @@ func (e *onErrLogger) Fatalln(v ...interface{}) (rtn Return) {
 	return rtn
 }
 
+func (e *onErrLogger) Fatalt(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Fatalt(t, v...)
+	}
+	return rtn
+}
+
 func (e *onErrLogger) Panic(v ...interface{}) (rtn Return) {
 	rtn.err = e.err
 	if e.popOnErr(v) {
@@ -478,3 +781,139 @@ func (e *onErrLogger) Panicln(v ...interface{}) (rtn Return) {
 	}
 	return rtn
 }
+
+func (e *onErrLogger) Panict(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Panict(t, v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Notice(v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Notice(v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Noticef(f string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Noticef(f, v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Noticeln(v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Noticeln(v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Noticet(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Noticet(t, v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Critical(v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Critical(v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Criticalf(f string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Criticalf(f, v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Criticalln(v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Criticalln(v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Criticalt(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Criticalt(t, v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Alert(v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Alert(v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Alertf(f string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Alertf(f, v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Alertln(v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Alertln(v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Alertt(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Alertt(t, v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Emergency(v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Emergency(v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Emergencyf(f string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Emergencyf(f, v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Emergencyln(v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Emergencyln(v...)
+	}
+	return rtn
+}
+
+func (e *onErrLogger) Emergencyt(t string, v ...interface{}) (rtn Return) {
+	rtn.err = e.err
+	if e.popOnErr(v) {
+		e.b.Emergencyt(t, v...)
+	}
+	return rtn
+}
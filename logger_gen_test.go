@@ -1,6 +1,6 @@
 // GENERATED BY ./gen/main.go; DO NOT EDIT THIS FILE
 // ~~ This file is not generated by hand ~~
-// ~~ generated on: 2020-02-22 04:38:59.3937254 +0000 UTC m=+0.747119401 ~~
+// ~~ generated on: 2026-10-17 20:07:03.952549077 +0000 UTC m=+0.008876861 ~~
 package logger
 
 import (
@@ -62,7 +62,31 @@ func TestLogger(t *testing.T) {
 			method: log.Fatal,
 			inputs: []interface{}{"abc", "def", "ghi"},
 			fatal:  888,
-			want: "Jan-01-2000 \x1b[31mFATAL: abcdefghi[0m\n",
+			want:   "Jan-01-2000 \x1b[31mFATAL: abcdefghi[0m\n",
+		}, {
+			name:   "log.Notice",
+			method: log.Notice,
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[37mNOTICE: abcdefghi[0m\n",
+		}, {
+			name:   "log.Critical",
+			method: log.Critical,
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mCRITICAL: abcdefghi[0m\n",
+		}, {
+			name:   "log.Alert",
+			method: log.Alert,
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mALERT: abcdefghi[0m\n",
+		}, {
+			name:   "log.Emergency",
+			method: log.Emergency,
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mEMERGENCY: abcdefghi[0m\n",
 		}, {
 			name:   "log.Printf",
 			method: log.Printf,
@@ -111,7 +135,35 @@ func TestLogger(t *testing.T) {
 			format: "%s %[3]s %[1]s %[2]s",
 			inputs: []interface{}{"abc", "def", "ghi"},
 			fatal:  888,
-			want: "Jan-01-2000 \x1b[31mFATAL: abc ghi abc def[0m\n",
+			want:   "Jan-01-2000 \x1b[31mFATAL: abc ghi abc def[0m\n",
+		}, {
+			name:   "log.Noticef",
+			method: log.Noticef,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[37mNOTICE: abc ghi abc def[0m\n",
+		}, {
+			name:   "log.Criticalf",
+			method: log.Criticalf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mCRITICAL: abc ghi abc def[0m\n",
+		}, {
+			name:   "log.Alertf",
+			method: log.Alertf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mALERT: abc ghi abc def[0m\n",
+		}, {
+			name:   "log.Emergencyf",
+			method: log.Emergencyf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mEMERGENCY: abc ghi abc def[0m\n",
 		}, {
 			name:   "log.Println",
 			method: log.Println,
@@ -153,7 +205,31 @@ func TestLogger(t *testing.T) {
 			method: log.Fatalln,
 			inputs: []interface{}{"abc", "def", "ghi"},
 			fatal:  888,
-			want: "Jan-01-2000 \x1b[31mFATAL: abc def ghi[0m\n",
+			want:   "Jan-01-2000 \x1b[31mFATAL: abc def ghi[0m\n",
+		}, {
+			name:   "log.Noticeln",
+			method: log.Noticeln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[37mNOTICE: abc def ghi[0m\n",
+		}, {
+			name:   "log.Criticalln",
+			method: log.Criticalln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mCRITICAL: abc def ghi[0m\n",
+		}, {
+			name:   "log.Alertln",
+			method: log.Alertln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mALERT: abc def ghi[0m\n",
+		}, {
+			name:   "log.Emergencyln",
+			method: log.Emergencyln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+
+			want: "Jan-01-2000 \x1b[31mEMERGENCY: abc def ghi[0m\n",
 		}}
 
 	for _, test := range tests {
@@ -247,6 +323,26 @@ func TestOnErrFalse(t *testing.T) {
 			method: log.Fatal,
 			inputs: []interface{}{"abc", "def", "ghi"},
 			want:   "",
+		}, {
+			name:   "log.Notice OnErr:False",
+			method: log.Notice,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Critical OnErr:False",
+			method: log.Critical,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Alert OnErr:False",
+			method: log.Alert,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Emergency OnErr:False",
+			method: log.Emergency,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
 		}, {
 			name:   "log.Printf OnErr:False",
 			method: log.Printf,
@@ -289,6 +385,30 @@ func TestOnErrFalse(t *testing.T) {
 			format: "%s %[3]s %[1]s %[2]s",
 			inputs: []interface{}{"abc", "def", "ghi"},
 			want:   "",
+		}, {
+			name:   "log.Noticef OnErr:False",
+			method: log.Noticef,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Criticalf OnErr:False",
+			method: log.Criticalf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Alertf OnErr:False",
+			method: log.Alertf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Emergencyf OnErr:False",
+			method: log.Emergencyf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
 		}, {
 			name:   "log.Println OnErr:False",
 			method: log.Println,
@@ -324,6 +444,26 @@ func TestOnErrFalse(t *testing.T) {
 			method: log.Fatalln,
 			inputs: []interface{}{"abc", "def", "ghi"},
 			want:   "",
+		}, {
+			name:   "log.Noticeln OnErr:False",
+			method: log.Noticeln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Criticalln OnErr:False",
+			method: log.Criticalln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Alertln OnErr:False",
+			method: log.Alertln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
+		}, {
+			name:   "log.Emergencyln OnErr:False",
+			method: log.Emergencyln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "",
 		}}
 
 	for _, test := range tests {
@@ -394,32 +534,52 @@ func TestOnErrTrue(t *testing.T) {
 			name:   "log.Info OnErr:True",
 			method: log.Info,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[32mINFO: abcdefghi[0m\n",
+			want:   "Jan-01-2000 \x1b[32mINFO: abcdefghi[0m\n",
 		}, {
 			name:   "log.Warn OnErr:True",
 			method: log.Warn,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[33mWARN: abcdefghi[0m\n",
+			want:   "Jan-01-2000 \x1b[33mWARN: abcdefghi[0m\n",
 		}, {
 			name:   "log.Debug OnErr:True",
 			method: log.Debug,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[36mDEBUG: abcdefghi[0m\n",
+			want:   "Jan-01-2000 \x1b[36mDEBUG: abcdefghi[0m\n",
 		}, {
 			name:   "log.Error OnErr:True",
 			method: log.Error,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[35mERROR: abcdefghi[0m\n",
+			want:   "Jan-01-2000 \x1b[35mERROR: abcdefghi[0m\n",
 		}, {
 			name:   "log.Trace OnErr:True",
 			method: log.Trace,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[34mTRACE: abcdefghi[0m\n",
+			want:   "Jan-01-2000 \x1b[34mTRACE: abcdefghi[0m\n",
 		}, {
 			name:   "log.Fatal OnErr:True",
 			method: log.Fatal,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[31mFATAL: abcdefghi[0m\n",
+			want:   "Jan-01-2000 \x1b[31mFATAL: abcdefghi[0m\n",
+		}, {
+			name:   "log.Notice OnErr:True",
+			method: log.Notice,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[37mNOTICE: abcdefghi[0m\n",
+		}, {
+			name:   "log.Critical OnErr:True",
+			method: log.Critical,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mCRITICAL: abcdefghi[0m\n",
+		}, {
+			name:   "log.Alert OnErr:True",
+			method: log.Alert,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mALERT: abcdefghi[0m\n",
+		}, {
+			name:   "log.Emergency OnErr:True",
+			method: log.Emergency,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mEMERGENCY: abcdefghi[0m\n",
 		}, {
 			name:   "log.Printf OnErr:True",
 			method: log.Printf,
@@ -431,37 +591,61 @@ func TestOnErrTrue(t *testing.T) {
 			method: log.Infof,
 			format: "%s %[3]s %[1]s %[2]s",
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[32mINFO: abc ghi abc def[0m\n",
+			want:   "Jan-01-2000 \x1b[32mINFO: abc ghi abc def[0m\n",
 		}, {
 			name:   "log.Warnf OnErr:True",
 			method: log.Warnf,
 			format: "%s %[3]s %[1]s %[2]s",
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[33mWARN: abc ghi abc def[0m\n",
+			want:   "Jan-01-2000 \x1b[33mWARN: abc ghi abc def[0m\n",
 		}, {
 			name:   "log.Debugf OnErr:True",
 			method: log.Debugf,
 			format: "%s %[3]s %[1]s %[2]s",
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[36mDEBUG: abc ghi abc def[0m\n",
+			want:   "Jan-01-2000 \x1b[36mDEBUG: abc ghi abc def[0m\n",
 		}, {
 			name:   "log.Errorf OnErr:True",
 			method: log.Errorf,
 			format: "%s %[3]s %[1]s %[2]s",
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[35mERROR: abc ghi abc def[0m\n",
+			want:   "Jan-01-2000 \x1b[35mERROR: abc ghi abc def[0m\n",
 		}, {
 			name:   "log.Tracef OnErr:True",
 			method: log.Tracef,
 			format: "%s %[3]s %[1]s %[2]s",
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[34mTRACE: abc ghi abc def[0m\n",
+			want:   "Jan-01-2000 \x1b[34mTRACE: abc ghi abc def[0m\n",
 		}, {
 			name:   "log.Fatalf OnErr:True",
 			method: log.Fatalf,
 			format: "%s %[3]s %[1]s %[2]s",
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[31mFATAL: abc ghi abc def[0m\n",
+			want:   "Jan-01-2000 \x1b[31mFATAL: abc ghi abc def[0m\n",
+		}, {
+			name:   "log.Noticef OnErr:True",
+			method: log.Noticef,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[37mNOTICE: abc ghi abc def[0m\n",
+		}, {
+			name:   "log.Criticalf OnErr:True",
+			method: log.Criticalf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mCRITICAL: abc ghi abc def[0m\n",
+		}, {
+			name:   "log.Alertf OnErr:True",
+			method: log.Alertf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mALERT: abc ghi abc def[0m\n",
+		}, {
+			name:   "log.Emergencyf OnErr:True",
+			method: log.Emergencyf,
+			format: "%s %[3]s %[1]s %[2]s",
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mEMERGENCY: abc ghi abc def[0m\n",
 		}, {
 			name:   "log.Println OnErr:True",
 			method: log.Println,
@@ -471,32 +655,52 @@ func TestOnErrTrue(t *testing.T) {
 			name:   "log.Infoln OnErr:True",
 			method: log.Infoln,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[32mINFO: abc def ghi[0m\n",
+			want:   "Jan-01-2000 \x1b[32mINFO: abc def ghi[0m\n",
 		}, {
 			name:   "log.Warnln OnErr:True",
 			method: log.Warnln,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[33mWARN: abc def ghi[0m\n",
+			want:   "Jan-01-2000 \x1b[33mWARN: abc def ghi[0m\n",
 		}, {
 			name:   "log.Debugln OnErr:True",
 			method: log.Debugln,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[36mDEBUG: abc def ghi[0m\n",
+			want:   "Jan-01-2000 \x1b[36mDEBUG: abc def ghi[0m\n",
 		}, {
 			name:   "log.Errorln OnErr:True",
 			method: log.Errorln,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[35mERROR: abc def ghi[0m\n",
+			want:   "Jan-01-2000 \x1b[35mERROR: abc def ghi[0m\n",
 		}, {
 			name:   "log.Traceln OnErr:True",
 			method: log.Traceln,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[34mTRACE: abc def ghi[0m\n",
+			want:   "Jan-01-2000 \x1b[34mTRACE: abc def ghi[0m\n",
 		}, {
 			name:   "log.Fatalln OnErr:True",
 			method: log.Fatalln,
 			inputs: []interface{}{"abc", "def", "ghi"},
-			want: "Jan-01-2000 \x1b[31mFATAL: abc def ghi[0m\n",
+			want:   "Jan-01-2000 \x1b[31mFATAL: abc def ghi[0m\n",
+		}, {
+			name:   "log.Noticeln OnErr:True",
+			method: log.Noticeln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[37mNOTICE: abc def ghi[0m\n",
+		}, {
+			name:   "log.Criticalln OnErr:True",
+			method: log.Criticalln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mCRITICAL: abc def ghi[0m\n",
+		}, {
+			name:   "log.Alertln OnErr:True",
+			method: log.Alertln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mALERT: abc def ghi[0m\n",
+		}, {
+			name:   "log.Emergencyln OnErr:True",
+			method: log.Emergencyln,
+			inputs: []interface{}{"abc", "def", "ghi"},
+			want:   "Jan-01-2000 \x1b[31mEMERGENCY: abc def ghi[0m\n",
 		}}
 
 	for _, test := range tests {
@@ -3,8 +3,11 @@ package logger
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	logg "log"
 	"math/rand"
@@ -12,7 +15,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
@@ -20,8 +27,17 @@ import (
 	"time"
 
 	"github.com/njones/logger/color"
+	"github.com/njones/logger/kv"
 )
 
+func TestMain(m *testing.M) {
+	// the color tests expect the escape codes in a bytes.Buffer, so color is
+	// forced for every writer that isn't a NoColorWriter
+	os.Unsetenv("NO_COLOR")
+	os.Setenv("FORCE_COLOR", "1")
+	os.Exit(m.Run())
+}
+
 func BenchmarkPrintln(b *testing.B) {
 	b.ReportAllocs()
 	l := New(WithOutput(ioutil.Discard))
@@ -97,6 +113,292 @@ func BenchmarkLogPrintln(b *testing.B) {
 	}
 }
 
+func TestAsyncWriter(t *testing.T) {
+	// gated returns a writer that holds the first write until the gate is opened
+	gated := func(have *bytes.Buffer) (w io.Writer, started, gate chan struct{}) {
+		var once sync.Once
+		started, gate = make(chan struct{}), make(chan struct{})
+		return writerFunc(func(p []byte) (int, error) {
+			once.Do(func() { close(started) })
+			<-gate
+			return have.Write(p)
+		}), started, gate
+	}
+
+	tests := []struct {
+		name    string
+		policy  asyncPolicy
+		want    string
+		dropped uint64
+	}{
+		{name: "block", policy: AsyncBlock, want: "abc"},
+		{name: "drop newest", policy: AsyncDropNewest, want: "ab", dropped: 1},
+		{name: "drop oldest", policy: AsyncDropOldest, want: "ac", dropped: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			have := new(bytes.Buffer)
+			w, started, gate := gated(have)
+			aw := AsyncWriter(w, AsyncQueue(1), test.policy)
+
+			aw.Write([]byte("a"))
+			<-started // "a" is being written
+			aw.Write([]byte("b"))
+
+			written := make(chan struct{})
+			go func() { aw.Write([]byte("c")); close(written) }()
+			if test.policy != AsyncBlock {
+				<-written
+			}
+			close(gate)
+			<-written
+
+			if err := aw.Flush(context.Background()); err != nil {
+				tt.Fatal(err)
+			}
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+			if aw.Dropped() != test.dropped {
+				tt.Errorf("\nhave: %d\nwant: %d\n", aw.Dropped(), test.dropped)
+			}
+		})
+	}
+
+	t.Run("flush timeout", func(tt *testing.T) {
+		w, started, gate := gated(new(bytes.Buffer))
+		aw := AsyncWriter(w)
+		defer aw.Close()
+		defer close(gate)
+
+		aw.Write([]byte("a"))
+		<-started
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := aw.Flush(ctx); err != context.DeadlineExceeded {
+			tt.Errorf("\nhave: %v\nwant: %v\n", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("flush dropped", func(tt *testing.T) {
+		w, started, gate := gated(new(bytes.Buffer))
+		aw := AsyncWriter(w, AsyncQueue(1), AsyncDropOldest)
+		defer aw.Close()
+
+		aw.Write([]byte("a"))
+		<-started // "a" is being written
+		flushed := make(chan error, 1)
+		go func() { flushed <- aw.Flush(context.Background()) }()
+		for len(aw.(*asyncWriter).queue) == 0 {
+			time.Sleep(time.Millisecond) // wait for the Flush marker
+		}
+		aw.Write([]byte("b")) // drops the Flush marker
+
+		select {
+		case <-flushed:
+			close(gate)
+			tt.Fatal("Flush returned while \"a\" was being written")
+		case <-time.After(20 * time.Millisecond):
+		}
+		close(gate)
+		if err := <-flushed; err != nil {
+			tt.Fatal(err)
+		}
+	})
+
+	t.Run("logger", func(tt *testing.T) {
+		have := new(bytes.Buffer)
+		aw := AsyncWriter(noColorWriter{have})
+		log := New(WithOutput(aw), WithTimeText("Jan-01-2000"))
+		log.Info("abc")
+		log.Warn("def")
+
+		if err := aw.Close(); err != nil {
+			tt.Fatal(err)
+		}
+		if want := "Jan-01-2000 INFO: abc\nJan-01-2000 WARN: def\n"; have.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+		}
+		if _, err := aw.Write([]byte("ghi")); err != io.ErrClosedPipe {
+			tt.Errorf("\nhave: %v\nwant: %v\n", err, io.ErrClosedPipe)
+		}
+	})
+}
+
+func TestClose(t *testing.T) {
+	// settled waits for the goroutines to get back down to n
+	settled := func(n int) int {
+		for i := 0; i < 100 && runtime.NumGoroutine() > n; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return runtime.NumGoroutine()
+	}
+
+	t.Run("goroutines", func(tt *testing.T) {
+		var before = runtime.NumGoroutine()
+
+		have, async, filtered := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+		log := New(WithOutput(noColorWriter{have}, FilterWriter(noColorWriter{filtered})), WithTimeText("Jan-01-2000"))
+		log.SetOutput(FilterWriter(noColorWriter{have}))
+		with := log.With(WithOutput(FilterWriter(noColorWriter{filtered}), AsyncWriter(noColorWriter{async})))
+		std := NewLog(nil, WithOutput(FilterWriter(noColorWriter{have})), WithTimeText("Jan-01-2000"))
+
+		log.Info("abc")
+		with.Info("def")
+		std.Println("ghi")
+
+		if err := CloseLog(std); err != nil {
+			tt.Fatal(err)
+		}
+		if err := with.Close(); err != nil {
+			tt.Fatal(err)
+		}
+		if err := log.Close(); err != nil {
+			tt.Fatal(err)
+		}
+
+		if after := settled(before); after > before {
+			tt.Errorf("\nhave: %d\nwant: %d\n", after, before)
+		}
+		if want := "Jan-01-2000 INFO: abc\nJan-01-2000 ghi\n"; have.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+		}
+		if want := "Jan-01-2000 INFO: def\n"; async.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", async.String(), want)
+		}
+	})
+
+	t.Run("closed writers", func(tt *testing.T) {
+		w, cw := &closeWriter{Writer: new(bytes.Buffer)}, &closeWriter{Writer: new(bytes.Buffer)}
+		log := New(WithOutput(w))
+		log.With(WithOutput(cw)).Close()
+
+		if w.closed != 0 || cw.closed != 1 { // the writers of the parent are left open
+			tt.Errorf("\nhave: %d, %d\nwant: %d, %d\n", w.closed, cw.closed, 0, 1)
+		}
+		log.Close()
+		log.Close()
+		if w.closed != 1 {
+			tt.Errorf("\nhave: %d\nwant: %d\n", w.closed, 1)
+		}
+	})
+
+	t.Run("partial line", func(tt *testing.T) {
+		have := new(bytes.Buffer)
+		log := New(WithOutput(FilterWriter(noColorWriter{have})))
+		log.Writer().Write([]byte("partial"))
+		if err := log.Close(); err != nil {
+			tt.Fatal(err)
+		}
+		if want := "partial\n"; have.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+		}
+
+		have.Reset()
+		log = New(WithOutput(FilterWriter(noColorWriter{have})), WithEncoder(EncoderFunc(func(w io.Writer, e *Entry) error {
+			_, err := io.WriteString(w, e.Message) // no newline
+			return err
+		})))
+		log.Info("abc")
+		log.Info("def")
+		if err := log.Close(); err != nil {
+			tt.Fatal(err)
+		}
+		if want := "abcdef\n"; have.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+		}
+	})
+
+	t.Run("set output", func(tt *testing.T) {
+		var before = runtime.NumGoroutine()
+
+		have := new(bytes.Buffer)
+		log := New(WithOutput(FilterWriter(noColorWriter{have})), WithTimeText("Jan-01-2000"))
+		for i := 0; i < 10; i++ {
+			log.SetOutput(FilterWriter(noColorWriter{have})) // the replaced scanner is stopped
+		}
+		if after := settled(before + 1); after > before+1 {
+			tt.Errorf("\nhave: %d\nwant: %d\n", after, before+1)
+		}
+
+		log.Info("abc")
+		log.Close()
+		if after := settled(before); after > before {
+			tt.Errorf("\nhave: %d\nwant: %d\n", after, before)
+		}
+		if want := "Jan-01-2000 INFO: abc\n"; have.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+		}
+	})
+
+	t.Run("with", func(tt *testing.T) {
+		var before = runtime.NumGoroutine()
+
+		have, filtered := new(bytes.Buffer), new(bytes.Buffer)
+		log := New(WithOutput(FilterWriter(noColorWriter{have})), WithTimeText("Jan-01-2000"))
+		child := log.With(WithOutput(FilterWriter(noColorWriter{filtered})))
+		child.Close() // doesn't close the parent
+		inherit := log.With()
+		log.Close() // the scanner is still used by inherit
+
+		if after := settled(before + 1); after > before+1 {
+			tt.Errorf("\nhave: %d\nwant: %d\n", after, before+1)
+		}
+		inherit.Info("abc")
+		inherit.Close()
+		if after := settled(before); after > before {
+			tt.Errorf("\nhave: %d\nwant: %d\n", after, before)
+		}
+		if want := "Jan-01-2000 INFO: abc\n"; have.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+		}
+	})
+
+	t.Run("sync", func(tt *testing.T) {
+		have := new(bytes.Buffer)
+		log := New(WithOutput(AsyncWriter(noColorWriter{writerFunc(func(p []byte) (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return have.Write(p)
+		})})), WithTimeText("Jan-01-2000"))
+		defer log.Close()
+
+		log.Info("abc")
+		if err := log.Sync(); err != nil {
+			tt.Fatal(err)
+		}
+		if want := "Jan-01-2000 INFO: abc\n"; have.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+		}
+	})
+
+	t.Run("sync timeout", func(tt *testing.T) {
+		defer func(d time.Duration) { syncTimeout = d }(syncTimeout)
+		syncTimeout = 10 * time.Millisecond
+
+		gate := make(chan struct{})
+		log := New(WithOutput(AsyncWriter(writerFunc(func(p []byte) (int, error) {
+			<-gate // a stuck writer
+			return len(p), nil
+		}))))
+		defer log.Close()
+		defer close(gate)
+
+		log.Info("abc")
+		if err := log.Sync(); err != context.DeadlineExceeded {
+			tt.Errorf("\nhave: %v\nwant: %v\n", err, context.DeadlineExceeded)
+		}
+	})
+}
+
+// closeWriter counts the times it's closed
+type closeWriter struct {
+	io.Writer
+	closed int
+}
+
+func (cw *closeWriter) Close() error { cw.closed++; return nil }
+
 func TestColor(t *testing.T) {
 	have := new(bytes.Buffer)
 	log := New(WithOutput(have), WithTimeText("Jan-01-2000"))
@@ -175,6 +477,106 @@ func TestColor(t *testing.T) {
 	}
 }
 
+func TestColorDetect(t *testing.T) {
+	f, err := ioutil.TempFile("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	buf := new(bytes.Buffer)
+
+	os.Unsetenv("FORCE_COLOR") // set by TestMain
+	defer os.Setenv("FORCE_COLOR", "1")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		w    io.Writer
+		want bool
+	}{
+		{
+			name: "buffer",
+			w:    buf,
+			want: false,
+		},
+		{
+			name: "buffer FORCE_COLOR",
+			env:  map[string]string{"FORCE_COLOR": "1"},
+			w:    buf,
+			want: true,
+		},
+		{
+			name: "bufio.Writer",
+			w:    bufio.NewWriter(buf),
+			want: false,
+		},
+		{
+			name: "file",
+			w:    f,
+			want: false,
+		},
+		{
+			name: "file FORCE_COLOR",
+			env:  map[string]string{"FORCE_COLOR": "1"},
+			w:    f,
+			want: true,
+		},
+		{
+			name: "buffer FORCE_COLOR=0",
+			env:  map[string]string{"FORCE_COLOR": "0"},
+			w:    buf,
+			want: false,
+		},
+		{
+			name: "buffer NO_COLOR",
+			env:  map[string]string{"NO_COLOR": "1"},
+			w:    buf,
+			want: false,
+		},
+		{
+			name: "buffer NO_COLOR and FORCE_COLOR",
+			env:  map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"},
+			w:    buf,
+			want: false,
+		},
+		{
+			name: "NoColorWriter FORCE_COLOR",
+			env:  map[string]string{"FORCE_COLOR": "1"},
+			w:    noColorWriter{buf},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		buf.Reset()
+		f.Truncate(0)
+		f.Seek(0, io.SeekStart)
+
+		t.Run(test.name, func(tt *testing.T) {
+			for k, v := range test.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			New(WithOutput(test.w), WithColor(color.Red)).Print("abc")
+			if bw, ok := test.w.(*bufio.Writer); ok {
+				bw.Flush()
+			}
+
+			have := buf.String()
+			if test.w == f {
+				b, _ := ioutil.ReadFile(f.Name())
+				have = string(b)
+			}
+			if has := strings.Contains(have, "\x1b["); has != test.want {
+				tt.Errorf("\nhave: %q\nwant color: %t\n", have, test.want)
+			}
+		})
+	}
+}
+
 func TestConcurrency(t *testing.T) {
 
 	have := new(bytes.Buffer)
@@ -225,6 +627,69 @@ func TestConcurrency(t *testing.T) {
 	}
 }
 
+func TestConsoleEncoder(t *testing.T) {
+	have := new(bytes.Buffer)
+	newLog := func(w io.Writer, opts ...consoleOpt) Logger {
+		return New(WithOutput(w), WithTimeText("Jan-01-2000"), WithEncoder(ConsoleEncoder(opts...)), WithOrderedFields())
+	}
+
+	tests := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{
+			name: "fields",
+			log: func() {
+				newLog(have).Info("abc", KV("user", "a b"), KV("error", "broken"), KV("n", 3))
+			},
+			want: "\x1b[2mJan-01-2000\x1b[0m \x1b[32mINFO: abc\x1b[0m \x1b[36muser=\x1b[0m\"a b\" \x1b[36merror=\x1b[0m\x1b[31mbroken\x1b[0m \x1b[36mn=\x1b[0m3\n",
+		},
+		{
+			name: "error value",
+			log:  func() { newLog(have).Warn("abc", KV("cause", fmt.Errorf("no"))) },
+			want: "\x1b[2mJan-01-2000\x1b[0m \x1b[33mWARN: abc\x1b[0m \x1b[36mcause=\x1b[0m\x1b[31mno\x1b[0m\n",
+		},
+		{
+			name: "event",
+			log:  func() { newLog(have).Event(Info).Int("n", 3).Err(nil).Msg("abc") },
+			want: "\x1b[2mJan-01-2000\x1b[0m \x1b[32mINFO: abc\x1b[0m \x1b[36mn=\x1b[0m3 \x1b[36merr=\x1b[0m\x1b[31m<nil>\x1b[0m\n",
+		},
+		{
+			name: "styles",
+			log: func() {
+				newLog(have, ConsoleStyles{Key: color.Style{Attr: color.Bold}, Value: color.Style{Fg: color.Blue}}).Info("abc", KV("n", 3))
+			},
+			want: "Jan-01-2000 \x1b[32mINFO: abc\x1b[0m \x1b[1mn=\x1b[0m\x1b[34m3\x1b[0m\n",
+		},
+		{
+			name: "no color writer",
+			log:  func() { newLog(noColorWriter{have}).Info("abc", KV("error", "broken")) },
+			want: "Jan-01-2000 INFO: abc error=broken\n",
+		},
+		{
+			name: "print",
+			log:  func() { newLog(have).Print("abc", KV("error", "broken"), KV("n", 3)) },
+			want: "\x1b[2mJan-01-2000\x1b[0m abc \x1b[36merror=\x1b[0m\x1b[31mbroken\x1b[0m \x1b[36mn=\x1b[0m3\n",
+		},
+		{
+			name: "print no color writer",
+			log:  func() { newLog(noColorWriter{have}).Print("abc", KV("error", "broken")) },
+			want: "Jan-01-2000 abc error=broken\n",
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.log()
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+}
+
 func TestDropCR(t *testing.T) {
 
 	w := &dropCRWriter{w: new(bytes.Buffer)}
@@ -279,6 +744,156 @@ func (cf customFloatFmt) Format(f fmt.State, c rune) {
 	fmt.Fprintf(f, "%.4f", float64(cf))
 }
 
+func TestEncoder(t *testing.T) {
+	have := new(bytes.Buffer)
+
+	var entry Entry
+	enc := EncoderFunc(func(w io.Writer, e *Entry) error {
+		entry = *e
+		_, err := fmt.Fprintf(w, "%s|%s|%s|%s|%v\n", e.Stamp, e.Level, e.Label, e.Message, e.Fields)
+		return err
+	})
+
+	log := New(WithOutput(have), WithTimeText("Jan-01-2000"), WithEncoder(enc))
+
+	tests := []struct {
+		name   string
+		method func(...interface{})
+		input  []interface{}
+		want   string
+		color  string
+	}{
+		{
+			name:   "log.Print",
+			method: log.Print,
+			input:  []interface{}{"abc", "def", KV("the", "quick")},
+			want:   "Jan-01-2000|||abcdef|[{the quick}]\n",
+		},
+		{
+			name:   "log.Warnln",
+			method: log.Warnln,
+			input:  []interface{}{KV("the", "quick"), "abc", KV("brown", "fox"), "def"},
+			want:   "Jan-01-2000|warn|WARN:|abc def|[{brown fox} {the quick}]\n",
+			color:  "\x1b[33m",
+		},
+		{
+			name:   "log.Info (NoColorWriter)",
+			method: log.With(WithOutput(noColorWriter{have})).Info,
+			input:  []interface{}{"abc"},
+			want:   "Jan-01-2000|info|INFO:|abc|[]\n",
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.method(test.input...)
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+			if string(entry.Color) != test.color {
+				tt.Errorf("\nhave: %q\nwant: %q\n", entry.Color, test.color)
+			}
+		})
+	}
+}
+
+func TestEvent(t *testing.T) {
+	have := new(bytes.Buffer)
+	log := New(WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000"))
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		event func()
+		want  string
+	}{
+		{
+			name:  "text",
+			event: func() { log.Event(Info).Str("user", "some one").Int("n", 3).Err(bytes.ErrTooLarge).Msg("done") },
+			want:  "Jan-01-2000 INFO: done user=some one, n=3, err=bytes.Buffer: too large\n",
+		},
+		{
+			name: "text all types",
+			event: func() {
+				log.Event(Warn).Int64("i", -1).Uint64("u", 2).Float64("f", 1.5).Bool("b", true).Err(nil).
+					Dur("d", 1500*time.Millisecond).Time("t", at).Send()
+			},
+			want: "Jan-01-2000 WARN:  i=-1, u=2, f=1.5, b=true, err=<nil>, d=1.5s, t=2020-01-02T03:04:05Z\n",
+		},
+		{
+			name: "text Field and Any",
+			event: func() {
+				New(WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000")).Field("the", "quick").
+					Event(Debug).Str("z", "last").Any("fox", []int{1}).Msgf("%s %d", "a", 1)
+			},
+			want: "Jan-01-2000 DEBUG: a 1 fox.0=1, the=quick, z=last\n",
+		},
+		{
+			name: "logfmt",
+			event: func() {
+				New(WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000"), WithKVMarshaler(kv.MarshalLogfmt)).
+					Event(Error).Str("user", "some one").Int("n", 3).Msg("done")
+			},
+			want: "Jan-01-2000 ERROR: done user=\"some one\" n=3\n",
+		},
+		{
+			name: "json",
+			event: func() {
+				New(WithOutput(have), WithTimeText("Jan-01-2000"), WithEncoder(JSONEncoder()), withTime(jsonTime)).
+					Event(Info).Str("user", "some one").Float64("f", 0.5).Err(nil).Time("t", at).Msg("done")
+			},
+			want: `{"time":"2000-01-01T00:00:00Z","level":"info","msg":"done","user":"some one","f":0.5,"err":null,"t":"2020-01-02T03:04:05Z"}` + "\n",
+		},
+		{
+			name: "json duplicate keys",
+			event: func() {
+				New(WithOutput(have), WithEncoder(JSONEncoder()), withTime(jsonTime)).Field("user", "field").
+					Event(Info).Str("user", "event").Int("n", 1).Int("n", 2).Msg("done")
+			},
+			want: `{"time":"2000-01-01T00:00:00Z","level":"info","msg":"done","user":"event","n":2}` + "\n",
+		},
+		{
+			name: "custom marshaler",
+			event: func() {
+				New(WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000"), WithKVMarshaler(json.Marshal)).
+					Event(Info).Str("user", "some one").Int("n", 3).Msg("done")
+			},
+			want: `Jan-01-2000 INFO: done {"n":3,"user":"some one"}` + "\n",
+		},
+		{
+			name:  "suppressed",
+			event: func() { log.With().Suppress(Trace).Event(Trace).Str("user", "some one").Msg("done") },
+			want:  "",
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.event()
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestEventAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector adds allocations")
+	}
+	for _, opt := range []optFunc{WithKVMarshaler(kv.Marshal), WithEncoder(JSONEncoder()), withTime(jsonTime)} {
+		log := New(WithOutput(ioutil.Discard), opt)
+		allocs := testing.AllocsPerRun(100, func() {
+			log.Event(Info).Str("user", "someone").Int("n", 3).Bool("ok", true).Err(bytes.ErrTooLarge).Msg("done")
+		})
+		if allocs != 0 {
+			t.Errorf("\nhave: %v\nwant: 0\n", allocs)
+		}
+	}
+}
+
 func TestFields(t *testing.T) {
 
 	have := new(bytes.Buffer)
@@ -307,6 +922,16 @@ func TestFields(t *testing.T) {
 			input:  []interface{}{"This is a", "test for field", KV("the", 0), KV("quick", true), KV("brown", 3.0), KV("fox", customFloatFmt(4.0))},
 			want:   "Jan-01-2000 This is a test for field brown=3, fox=4.0000, quick=true, the=0\n",
 		},
+		{
+			name:   "log KV nested values",
+			method: log.Println,
+			input: []interface{}{"This is a", "test for field", KV("user", struct {
+				ID    int      `kv:"id"`
+				Name  string   `kv:"name,omitempty"`
+				Roles []string `kv:"role"`
+			}{ID: 7, Roles: []string{"admin"}}), KV("the", &map[string]int{"quick": 1})},
+			want: "Jan-01-2000 This is a test for field the.quick=1, user.id=7, user.role.0=admin\n",
+		},
 		{
 			name:   "log.Println Field",
 			method: logField.Println,
@@ -375,15 +1000,159 @@ func TestFields(t *testing.T) {
 		},
 	}
 
-	for _, test := range tests {
-		have.Reset()
-		t.Run(test.name, func(tt *testing.T) {
-			test.method(test.input...)
-			if have.String() != test.want {
-				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
-			}
-		})
-	}
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.method(test.input...)
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestFileWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	clock := func() time.Time { at = at.Add(time.Second); return at }
+
+	read := func(path string) string {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err.Error()
+		}
+		if strings.HasSuffix(path, ".gz") {
+			zr, err := gzip.NewReader(bytes.NewReader(b))
+			if err != nil {
+				return err.Error()
+			}
+			b, _ = ioutil.ReadAll(zr)
+		}
+		return string(b)
+	}
+	list := func(sub string) (names []string) {
+		files, _ := ioutil.ReadDir(filepath.Join(dir, sub))
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		return names
+	}
+
+	t.Run("rotate", func(tt *testing.T) {
+		fw := FileWriter(filepath.Join(dir, "size", "app.log"), FileMaxSize(6), FileMaxBackups(2), FileCompress(true))
+		fw.(*fileWriter).now = clock
+		for _, s := range []string{"one\n", "two\n", "three\n", "four\n", "five\n"} {
+			fw.Write([]byte(s))
+		}
+		fw.(io.Closer).Close()
+
+		want := []string{"app-2020-01-02T03-04-08.000.log.gz", "app-2020-01-02T03-04-09.000.log.gz", "app.log"}
+		if have := list("size"); !reflect.DeepEqual(have, want) {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+		if have, want := read(filepath.Join(dir, "size", want[1])), "four\n"; have != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+		if have, want := read(filepath.Join(dir, "size", "app.log")), "five\n"; have != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+	})
+
+	t.Run("rotate in the same millisecond", func(tt *testing.T) {
+		fw := FileWriter(filepath.Join(dir, "same", "app.log"), FileMaxSize(4), FileMaxBackups(2))
+		fw.(*fileWriter).now = func() time.Time { return at }
+		for _, s := range []string{"one\n", "two\n", "six\n", "ten\n"} {
+			fw.Write([]byte(s))
+		}
+		fw.(io.Closer).Close()
+
+		stamp := at.Format(backupStamp)
+		want := []string{"app-" + stamp + "-1.log", "app-" + stamp + "-2.log", "app.log"}
+		if have := list("same"); !reflect.DeepEqual(have, want) {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+		for i, content := range []string{"two\n", "six\n", "ten\n"} {
+			if have := read(filepath.Join(dir, "same", want[i])); have != content {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have, content)
+			}
+		}
+	})
+
+	t.Run("max age", func(tt *testing.T) {
+		os.MkdirAll(filepath.Join(dir, "age"), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "age", "app-2019-01-01T00-00-00.000.log"), []byte("old\n"), 0644)
+
+		fw := FileWriter(filepath.Join(dir, "age", "app.log"), FileMaxSize(5), FileMaxAge(24*time.Hour))
+		fw.(*fileWriter).now = clock
+		fw.Write([]byte("one\n"))
+		fw.Write([]byte("two\n"))
+		fw.(io.Closer).Close()
+
+		want := []string{"app-2020-01-02T03-04-10.000.log", "app.log"}
+		if have := list("age"); !reflect.DeepEqual(have, want) {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+	})
+
+	t.Run("file without a stamp", func(tt *testing.T) {
+		os.MkdirAll(filepath.Join(dir, "nostamp"), 0755)
+		ioutil.WriteFile(filepath.Join(dir, "nostamp", "app.log.gz"), []byte("keep\n"), 0644)
+
+		fw := FileWriter(filepath.Join(dir, "nostamp", "app.log"), FileMaxSize(5), FileMaxBackups(1), FileMaxAge(24*time.Hour))
+		fw.(*fileWriter).now = clock
+		fw.Write([]byte("one\n"))
+		fw.Write([]byte("two\n"))
+		fw.(io.Closer).Close()
+
+		want := []string{"app-2020-01-02T03-04-11.000.log", "app.log", "app.log.gz"}
+		if have := list("nostamp"); !reflect.DeepEqual(have, want) {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+	})
+
+	t.Run("path template", func(tt *testing.T) {
+		day := time.Date(2020, 1, 2, 10, 0, 0, 0, time.Local)
+		link := filepath.Join(dir, "daily", "current.log")
+
+		fw := FileWriter(filepath.Join(dir, "daily", "app-{2006-01-02}.log"), FileMaxBackups(1), FileCompress(true), FileSymlink(link))
+		fw.(*fileWriter).now = func() time.Time { return day }
+		for _, s := range []string{"one\n", "two\n", "three\n"} {
+			fw.Write([]byte(s))
+			day = day.AddDate(0, 0, 1)
+		}
+		fw.Write([]byte("four\n"))
+		fw.(io.Closer).Close()
+
+		want := []string{"app-2020-01-04.log.gz", "app-2020-01-05.log", "current.log"}
+		if have := list("daily"); !reflect.DeepEqual(have, want) {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+		if have, want := read(filepath.Join(dir, "daily", want[0])), "three\n"; have != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+		if have, want := read(link), "four\n"; have != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+	})
+
+	t.Run("logger", func(tt *testing.T) {
+		path := filepath.Join(dir, "app.log")
+		log := New(WithOutput(FileWriter(path)), WithTimeText("Jan-01-2000")).(*baseLogger)
+		log.Info("abc")
+
+		if have := len(log.out.close.list); have != 1 {
+			tt.Errorf("\nhave: %d\nwant: %d\n", have, 1)
+		}
+		log.Close()
+		if have, want := read(path), "Jan-01-2000 INFO: abc\n"; have != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+	})
 }
 
 func TestFilename(t *testing.T) {
@@ -477,6 +1246,14 @@ func TestFilename(t *testing.T) {
 			depth: 50,
 			want:  regexp.MustCompile(`70-JAN-01 \?\?\?:0 abcdefghi\n`),
 		},
+		{
+			name:  "log.Output Package (no caller)",
+			flags: Lpackage | Lrelfile,
+			log:   log.With(WithTimeText("70-JAN-01")),
+			input: "abcdefghi",
+			depth: 50,
+			want:  regexp.MustCompile(`^70-JAN-01 \?\?\?:0 abcdefghi\n`),
+		},
 	}
 
 	for _, test := range tests {
@@ -505,6 +1282,54 @@ func TestFilename(t *testing.T) {
 			log.(*baseLogger).ts.stamp = defaultTS // reset before the next time
 		})
 	}
+
+	t.Run("caller", func(tt *testing.T) {
+		have := new(bytes.Buffer)
+		log := New(WithOutput(noColorWriter{have}), WithTimeText("70-JAN-01"))
+		log.SetFlags(Lpackage | Lrelfile | Lfuncname)
+		info := log.Info // a method value has an <autogenerated> frame
+
+		// each log returns the line it logged on
+		tests := []struct {
+			name string
+			log  func() int
+		}{
+			{name: "log.Print", log: func() int { _, _, n, _ := runtime.Caller(0); log.Print("abc"); return n }},
+			{name: "log.Info", log: func() int { _, _, n, _ := runtime.Caller(0); log.Info("abc"); return n }},
+			{name: "log.Infof", log: func() int { _, _, n, _ := runtime.Caller(0); log.Infof("%s", "abc"); return n }},
+			{name: "log.Info (method value)", log: func() int { _, _, n, _ := runtime.Caller(0); info("abc"); return n }},
+			{name: "log.Log", log: func() int { _, _, n, _ := runtime.Caller(0); log.Log(Info, "abc"); return n }},
+			{name: "log.Event", log: func() int { _, _, n, _ := runtime.Caller(0); log.Event(Info).Msg("abc"); return n }},
+			{name: "log.OnErr", log: func() int { _, _, n, _ := runtime.Caller(0); log.OnErr(bytes.ErrTooLarge).Info("abc"); return n }},
+			{name: "log.With", log: func() int { _, _, n, _ := runtime.Caller(0); log.With().Info("abc"); return n }},
+		}
+
+		for _, test := range tests {
+			have.Reset()
+			n := test.log()
+			want := regexp.MustCompile(fmt.Sprintf(`^70-JAN-01 github\.com/njones/logger logger_test\.go:%d logger\.TestFilename\.func[\d.]+ (INFO: )?abc\n$`, n))
+			if !want.MatchString(have.String()) {
+				tt.Errorf("%s\nhave: %q\nwant: %q\n", test.name, have.String(), want.String())
+			}
+		}
+	})
+
+	t.Run("relative Filename in a sub package", func(tt *testing.T) {
+		_, file, _, _ := runtime.Caller(0)
+		if have, want := relFile(path.Join(path.Dir(file), "kv", "append.go")), "kv/append.go"; have != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+		if have, want := relFile("/no/module/file.go"), "/no/module/file.go"; have != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+	})
+
+	t.Run("split Func name", func(tt *testing.T) {
+		pkg, name := splitFuncName("github.com/a/b/internal/db.(*Pool).Get")
+		if have, want := pkg+" "+name, "github.com/a/b/internal/db db.(*Pool).Get"; have != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have, want)
+		}
+	})
 }
 
 func TestFilterWriter(t *testing.T) {
@@ -701,6 +1526,272 @@ func TestHttpHandler(t *testing.T) {
 	}
 }
 
+func TestJSONEncoder(t *testing.T) {
+	have := new(bytes.Buffer)
+	log := New(WithOutput(have), WithTimeText("Jan-01-2000"), WithEncoder(JSONEncoder()), withTime(jsonTime))
+
+	tests := []struct {
+		name   string
+		method func(...interface{})
+		input  []interface{}
+		want   string
+	}{
+		{
+			name:   "log.Print",
+			method: log.Print,
+			input:  []interface{}{"abc", "def"},
+			want:   `{"time":"2000-01-01T00:00:00Z","msg":"abcdef"}` + "\n",
+		},
+		{
+			name:   "log.Info KV",
+			method: log.Info,
+			input:  []interface{}{"The \"quick\"\tbrown fox", KV("user", "someone"), KV("n", 3), KV("err", bytes.ErrTooLarge)},
+			want:   `{"time":"2000-01-01T00:00:00Z","level":"info","msg":"The \"quick\"\tbrown fox","err":"bytes.Buffer: too large","n":3,"user":"someone"}` + "\n",
+		},
+		{
+			name:   "log.Warnln Field KVMap",
+			method: New(WithOutput(have), WithTimeText("Jan-01-2000"), WithEncoder(JSONEncoder()), withTime(jsonTime)).Field("the", "quick").Fields(map[string]interface{}{"brown": []string{"fox"}}).Warnln,
+			input:  []interface{}{"jumped", KVMap{"over": nil, "lazy": map[string]bool{"dog": true}}},
+			want:   `{"time":"2000-01-01T00:00:00Z","level":"warn","msg":"jumped","brown":["fox"],"lazy":{"dog":true},"over":null,"the":"quick"}` + "\n",
+		},
+		{
+			name:   "log.Error rendered values",
+			method: log.Error,
+			input: []interface{}{"rendered", KVMap{
+				"at":    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+				"took":  1500 * time.Millisecond,
+				"body":  []byte("ok"),
+				"addr":  net.IPv4(10, 0, 0, 1),
+				"ratio": customFloatFmt(1.5),
+			}},
+			want: `{"time":"2000-01-01T00:00:00Z","level":"error","msg":"rendered","addr":"10.0.0.1","at":"2020-01-02T03:04:05Z","body":"ok","ratio":1.5,"took":"1.5s"}` + "\n",
+		},
+		{
+			name:   "log.Info reserved keys",
+			method: log.With(withTime(jsonTime.Add(2 * time.Nanosecond))).Info,
+			input:  []interface{}{"abc", KV("msg", "x"), KV("time", 1), KV("user", "someone")},
+			want:   `{"time":"2000-01-01T00:00:00.000000002Z","level":"info","msg":"abc","fields.msg":"x","fields.time":1,"user":"someone"}` + "\n",
+		},
+		{
+			name:   "log.Debug no time (unmarshalable)",
+			method: New(WithOutput(have), WithTimeFormat(""), WithEncoder(JSONEncoder()), withTime(jsonTime)).Debug,
+			input:  []interface{}{"line1\nline2", KV("ch", make(chan int))},
+			want:   `{"level":"debug","msg":"line1\nline2","ch":"0x`,
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.method(test.input...)
+			if !strings.HasPrefix(have.String(), test.want) {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+			var m map[string]interface{}
+			if err := json.Unmarshal(have.Bytes(), &m); err != nil {
+				tt.Errorf("\nhave: %v\nwant: <nil>\n", err)
+			}
+		})
+	}
+}
+
+func TestLayout(t *testing.T) {
+	have := new(bytes.Buffer)
+	newLog := func(opts ...optFunc) Logger {
+		return New(append([]optFunc{WithOutput(have), WithTimeText("Jan-01-2000")}, opts...)...)
+	}
+	prefixed := func(l Logger) Logger { l.SetPrefix("[p]"); return l }
+
+	tests := []struct {
+		name string
+		log  func(Logger)
+		opts []optFunc
+	}{
+		{name: "print", log: func(l Logger) { l.Print("abc") }},
+		{name: "info", log: func(l Logger) { l.Info("abc", KV("a", 1)) }},
+		{name: "empty", log: func(l Logger) { l.Info() }},
+		{name: "prefix", log: func(l Logger) { prefixed(l).Warn("abc") }},
+		{name: "caller", log: func(l Logger) { l.SetFlags(Lshortfile); l.Error("abc") }, opts: []optFunc{WithTimeFormat("")}},
+		{name: "event", log: func(l Logger) { l.Event(Info).Int("n", 1).Str("s", "x").Msg("abc") }},
+		{name: "event fields", log: func(l Logger) { l.Event(Info).Any("a", 1).Int("n", 1).Send() }},
+		{name: "no color", log: func(l Logger) { l.Info("abc") }, opts: []optFunc{WithColor(color.NoColor)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name+" (default)", func(tt *testing.T) {
+			have.Reset()
+			test.log(newLog(test.opts...))
+			want := have.String()
+
+			have.Reset()
+			test.log(newLog(append(test.opts, WithLayout(DefaultLayout))...))
+			if have.String() != want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+			}
+		})
+	}
+
+	layouts := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{
+			name: "level first",
+			log:  func() { newLog(WithLayout("{level} {time} {msg} {prefix} {fields}")).Info("abc", KV("a", 1)) },
+			want: "\x1b[32mINFO:\x1b[0m Jan-01-2000 \x1b[32mabc\x1b[0m a=1\n",
+		},
+		{
+			name: "fields between level and msg",
+			log:  func() { newLog(WithLayout("{time} {level} {fields} {msg}")).Info("abc", KV("a", 1)) },
+			want: "Jan-01-2000 \x1b[32mINFO:\x1b[0m a=1 \x1b[32mabc\x1b[0m\n",
+		},
+		{
+			name: "fields after a dropped word",
+			log:  func() { newLog(WithLayout("{time} {level} {prefix} {msg} {fields}")).Info("abc", KV("a", 1)) },
+			want: "Jan-01-2000 \x1b[32mINFO: abc\x1b[0m a=1\n",
+		},
+		{
+			name: "prefix after msg",
+			log: func() {
+				prefixed(newLog(WithLayout("{time} {level} {msg} {prefix}"), WithColor(color.NoColor))).Info("abc")
+			},
+			want: "Jan-01-2000 INFO: abc [p]\n",
+		},
+		{
+			name: "dropped words",
+			log:  func() { newLog(WithLayout("{time} {level} [{caller}] {prefix}{msg} {fields}")).Print("abc") },
+			want: "Jan-01-2000 abc\n",
+		},
+		{
+			name: "text and unknown",
+			log:  func() { newLog(WithLayout("{time} | {{x}} {other} {level}{msg}")).Info("abc") },
+			want: "Jan-01-2000 | {x} {other} \x1b[32mINFO:abc\x1b[0m\n",
+		},
+	}
+
+	for _, test := range layouts {
+		t.Run(test.name, func(tt *testing.T) {
+			have.Reset()
+			test.log()
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestLevelColors(t *testing.T) {
+	have := new(bytes.Buffer)
+	log := New(WithOutput(have), WithTimeText("Jan-01-2000"), WithLevelColors(map[logLevel]color.Style{
+		Error: {Fg: color.BrightRed, Attr: color.Bold},
+		Fatal: {Fg: color.White, Bg: color.Red, Attr: color.Bold},
+		Warn:  {},
+		audit: {Fg: color.RGB{R: 255, G: 136}},
+	}))
+	log.FatalInt(0).(*baseLogger).exit.Func = func(int) {}
+
+	log.Info("abc")
+	log.Error("def")
+	log.Event(Fatal).Msg("ghi")
+	log.Warn("jkl")
+	log.Log(audit, "mno")
+
+	want := "Jan-01-2000 \x1b[32mINFO: abc\x1b[0m\n" +
+		"Jan-01-2000 \x1b[1;91mERROR: def\x1b[0m\n" +
+		"Jan-01-2000 \x1b[1;37;41mFATAL: ghi\x1b[0m\n" +
+		"Jan-01-2000 WARN: jkl\n" +
+		"Jan-01-2000 \x1b[38;2;255;136;0mAUDIT: mno\x1b[0m\n"
+	if have.String() != want {
+		t.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+	}
+}
+
+func TestLevelDisplay(t *testing.T) {
+	have := new(bytes.Buffer)
+	newLog := func(opts ...optFunc) Logger {
+		return New(append([]optFunc{WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000")}, opts...)...)
+	}
+	padded := WithLevelLabels(func(level logLevel) string { return fmt.Sprintf("%-5s", strings.ToUpper(level.String())) })
+	emoji := WithLevelLabels(func(level logLevel) string {
+		switch level {
+		case Info:
+			return "ℹ️"
+		case Warn:
+			return "⚠️"
+		}
+		return ""
+	})
+
+	tests := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{
+			name: "default",
+			log:  func() { newLog(WithLevelDisplay(DisplayDefault)).Warn("abc") },
+			want: "Jan-01-2000 WARN: abc\n",
+		},
+		{
+			name: "box",
+			log:  func() { newLog(WithLevelDisplay(DisplayBox)).Warn("abc") },
+			want: "Jan-01-2000 [WARN] abc\n",
+		},
+		{
+			name: "short",
+			log:  func() { newLog(WithLevelDisplay(DisplayShort)).Log(audit, "abc") },
+			want: "Jan-01-2000 AUD: abc\n",
+		},
+		{
+			name: "short box",
+			log:  func() { newLog(WithLevelDisplay(DisplayShortBox)).Event(Error).Msg("abc") },
+			want: "Jan-01-2000 [ERR] abc\n",
+		},
+		{
+			name: "padded labels",
+			log: func() {
+				log := newLog(padded)
+				log.Info("abc")
+				log.Error("def")
+				log.Log(security, "ghi")
+			},
+			want: "Jan-01-2000 INFO  abc\nJan-01-2000 ERROR def\nJan-01-2000 SECURITY ghi\n",
+		},
+		{
+			name: "emoji labels",
+			log: func() {
+				log := newLog(emoji)
+				log.Warn("abc")
+				log.Debug("def")
+			},
+			want: "Jan-01-2000 ⚠️ abc\nJan-01-2000 def\n",
+		},
+		{
+			name: "padded labels JSON",
+			log:  func() { newLog(padded, WithEncoder(JSONEncoder()), withTime(jsonTime)).Info("abc") },
+			want: `{"time":"2000-01-01T00:00:00Z","level":"INFO","msg":"abc"}` + "\n",
+		},
+		{
+			name: "box JSON",
+			log: func() {
+				newLog(WithLevelDisplay(DisplayBox), WithEncoder(JSONEncoder()), withTime(jsonTime)).Info("abc")
+			},
+			want: `{"time":"2000-01-01T00:00:00Z","level":"info","msg":"abc"}` + "\n",
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.log()
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+}
+
 func TestLogLogger(t *testing.T) {
 	var log *logg.Logger
 	var have = new(bytes.Buffer)
@@ -760,6 +1851,87 @@ func TestLogLogger(t *testing.T) {
 	}
 }
 
+type password string
+
+func (password) LogValue() interface{} { return "***" }
+
+type lazyValue struct{ calls *int }
+
+func (lv lazyValue) LogValue() interface{} {
+	*lv.calls++
+	return fmt.Sprintf("expensive(%d)", *lv.calls)
+}
+
+func TestLogValuer(t *testing.T) {
+	have := new(bytes.Buffer)
+	log := New(WithOutput(have), WithTimeText("Jan-01-2000"))
+
+	var calls int
+	lazy := lazyValue{calls: &calls}
+
+	tests := []struct {
+		name   string
+		method func(...interface{})
+		input  []interface{}
+		want   string
+		calls  int
+	}{
+		{
+			name:   "log.Print redacted",
+			method: log.Print,
+			input:  []interface{}{"login ", password("secret"), KV("pass", password("secret"))},
+			want:   "Jan-01-2000 login *** pass=***\n",
+		},
+		{
+			name:   "log.Println nested",
+			method: log.Println,
+			input: []interface{}{"login", KV("user", struct {
+				Name string   `kv:"name"`
+				Pass password `kv:"pass"`
+			}{"someone", "secret"})},
+			want: "Jan-01-2000 login user.name=someone, user.pass=***\n",
+		},
+		{
+			name:   "log.Println nested JSON",
+			method: New(WithOutput(have), WithTimeFormat(""), WithEncoder(JSONEncoder())).Println,
+			input: []interface{}{"login", KV("user", struct {
+				Name  string
+				Pass  password `json:"pass"`
+				Keys  []interface{}
+				Extra map[string]interface{} `json:",omitempty"`
+			}{"someone", "secret", []interface{}{password("a"), 1}, nil}), KV("m", map[string]interface{}{"pass": &struct{ Pass password }{"b"}})},
+			want: `{"msg":"login","m":{"pass":{"Pass":"***"}},"user":{"Name":"someone","pass":"***","Keys":["***",1]}}` + "\n",
+		},
+		{
+			name:   "log.Debug lazy",
+			method: log.Debug,
+			input:  []interface{}{"query", KV("dump", lazy)},
+			want:   "Jan-01-2000 \x1b[36mDEBUG: query\x1b[0m dump=expensive(1)\n",
+			calls:  1,
+		},
+		{
+			name:   "log.Debug lazy (suppressed)",
+			method: log.With(WithOutput(have)).Suppress(Debug).Debug,
+			input:  []interface{}{"query", KV("dump", lazy)},
+			want:   "",
+			calls:  1,
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.method(test.input...)
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+			if calls != test.calls {
+				tt.Errorf("\nhave: %d\nwant: %d\n", calls, test.calls)
+			}
+		})
+	}
+}
+
 func TestNetWriter(t *testing.T) {
 	var port = ":2000"
 	var setup = make(chan net.Listener)
@@ -767,7 +1939,10 @@ func TestNetWriter(t *testing.T) {
 	var have = make(chan string, 1)
 
 	go serveTCP(port, have, setup, teardown, t)
-	listener := <-setup
+	listener, ok := <-setup
+	if !ok {
+		t.FailNow()
+	}
 
 	log := New(WithOutput(NetWriter("tcp", port)), WithTimeText("Jan-01-2000"))
 
@@ -845,7 +2020,10 @@ func TestNetWriterMulti(t *testing.T) {
 	}
 
 	go serveTCP(port, have.net, setup, teardown, t)
-	listener := <-setup
+	listener, ok := <-setup
+	if !ok {
+		t.FailNow()
+	}
 
 	log := New(WithOutput(NetWriter("tcp", ":2000"), have.log), WithTimeText("Jan-01-2000"))
 
@@ -926,7 +2104,10 @@ func TestNetWriterTimeout(t *testing.T) {
 	var teardown = make(chan struct{}, 1)
 
 	go serveTCP(port, nil, setup, teardown, t)
-	listener := <-setup
+	listener, ok := <-setup
+	if !ok {
+		t.FailNow()
+	}
 
 	nw := NetWriter("tcp", port, tod(1*time.Nanosecond)).(*netWriter)
 	log := New(WithOutput(nw), WithTimeText("Jan-01-2000"))
@@ -948,7 +2129,10 @@ func TestNetWriterClose(t *testing.T) {
 	var teardown = make(chan struct{}, 1)
 
 	go serveTCP(port, nil, setup, teardown, t)
-	listener := <-setup
+	listener, ok := <-setup
+	if !ok {
+		t.FailNow()
+	}
 
 	nw := NetWriter("tcp", port).(*netWriter)
 	log := New(WithOutput(nw), WithTimeText("Jan-01-2000"))
@@ -983,27 +2167,101 @@ func TestOnErrValueExchange(t *testing.T) {
 			want:   "Jan-01-2000 abcdefghi: bytes.Buffer: too large\n",
 		},
 		{
-			name:   "log.Infof",
-			method: log.Infof,
-			format: "%s %s%[4]s: %[3]v",
-			inputs: []interface{}{"abc", "def", ErrSub{}, "ghi"},
-			want:   "Jan-01-2000 \x1b[32mINFO: abc defghi: bytes.Buffer: too large\x1b[0m\n",
+			name:   "log.Infof",
+			method: log.Infof,
+			format: "%s %s%[4]s: %[3]v",
+			inputs: []interface{}{"abc", "def", ErrSub{}, "ghi"},
+			want:   "Jan-01-2000 \x1b[32mINFO: abc defghi: bytes.Buffer: too large\x1b[0m\n",
+		},
+		{
+			name:   "log.Debugf mutli error",
+			method: log.Debugf,
+			format: "%s%s%s: %v: %v",
+			inputs: []interface{}{"abc", "def", "ghi", ErrSub{}, ErrSub{}},
+			want:   "Jan-01-2000 \x1b[36mDEBUG: abcdefghi: bytes.Buffer: too large: bytes.Buffer: too large\x1b[0m\n",
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.method.(func(string, ...interface{}) Return)(test.format, test.inputs...)
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\n\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestOrderedFields(t *testing.T) {
+	have := new(bytes.Buffer)
+	newLog := func(opts ...optFunc) Logger {
+		return New(append([]optFunc{WithOutput(have), WithTimeText("Jan-01-2000"), WithOrderedFields()}, opts...)...).
+			Field("the", "quick").Field("brown", "fox").Fields(map[string]interface{}{"lazy": "dog", "jumped": "over"})
+	}
+
+	tests := []struct {
+		name   string
+		method func(...interface{})
+		input  []interface{}
+		want   string
+	}{
+		{
+			name:   "log.Println Field(s)",
+			method: newLog().Println,
+			input:  []interface{}{"ordered"},
+			want:   "Jan-01-2000 ordered the=quick, brown=fox, jumped=over, lazy=dog\n",
+		},
+		{
+			name: "log.Println With siblings",
+			method: func() Logger {
+				p := New(WithOutput(have), WithTimeText("Jan-01-2000"), WithOrderedFields()).Field("x", 1).Field("y", 2).Field("z", 3)
+				a, c := p.With(), p.With()
+				a.Field("a", "A")
+				c.Field("c", "C")
+				return a
+			}().Println,
+			input: []interface{}{"ordered"},
+			want:  "Jan-01-2000 ordered x=1, y=2, z=3, a=A\n",
+		},
+		{
+			name:   "log.Println KV after fields",
+			method: newLog().Println,
+			input:  []interface{}{"ordered", KV("zebra", 1), KV("apple", 2)},
+			want:   "Jan-01-2000 ordered the=quick, brown=fox, jumped=over, lazy=dog, zebra=1, apple=2\n",
+		},
+		{
+			name:   "log.Println KV last value wins",
+			method: newLog().Println,
+			input:  []interface{}{"ordered", KV("zebra", 1), KV("brown", "bear"), KV("zebra", 3)},
+			want:   "Jan-01-2000 ordered the=quick, brown=bear, jumped=over, lazy=dog, zebra=3\n",
+		},
+		{
+			name:   "log.Println Field replaced",
+			method: newLog().Field("the", "slow").Println,
+			input:  []interface{}{"ordered"},
+			want:   "Jan-01-2000 ordered the=slow, brown=fox, jumped=over, lazy=dog\n",
+		},
+		{
+			name:   "log.Println logfmt",
+			method: newLog(WithKVMarshaler(kv.MarshalLogfmt)).Println,
+			input:  []interface{}{"ordered", KVMap{"b": 2, "a": "x y"}},
+			want:   "Jan-01-2000 ordered the=quick brown=fox jumped=over lazy=dog a=\"x y\" b=2\n",
 		},
 		{
-			name:   "log.Debugf mutli error",
-			method: log.Debugf,
-			format: "%s%s%s: %v: %v",
-			inputs: []interface{}{"abc", "def", "ghi", ErrSub{}, ErrSub{}},
-			want:   "Jan-01-2000 \x1b[36mDEBUG: abcdefghi: bytes.Buffer: too large: bytes.Buffer: too large\x1b[0m\n",
+			name:   "log.Println JSON",
+			method: newLog(WithEncoder(JSONEncoder()), withTime(jsonTime)).Println,
+			input:  []interface{}{"ordered", KV("zebra", 1)},
+			want:   `{"time":"2000-01-01T00:00:00Z","msg":"ordered","the":"quick","brown":"fox","jumped":"over","lazy":"dog","zebra":1}` + "\n",
 		},
 	}
 
 	for _, test := range tests {
 		have.Reset()
 		t.Run(test.name, func(tt *testing.T) {
-			test.method.(func(string, ...interface{}) Return)(test.format, test.inputs...)
+			test.method(test.input...)
 			if have.String() != test.want {
-				tt.Errorf("\nhave: %q\n\nwant: %q\n", have.String(), test.want)
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
 			}
 		})
 	}
@@ -1220,36 +2478,491 @@ func TestPrefix(t *testing.T) {
 	}
 }
 
+// the levels are registered once, so the test can run more than once
+func TestPrettyEncoder(t *testing.T) {
+	have := new(bytes.Buffer)
+
+	tests := []struct {
+		name  string
+		enc   Encoder
+		entry Entry
+		want  string
+	}{
+		{
+			name:  "caller and level widths",
+			enc:   PrettyEncoder(ConsoleCallerWidth(10), ConsoleLevelWidth(6)),
+			entry: Entry{Stamp: []byte("Jan-01-2000"), Caller: "a.go:1", Label: []byte("INFO:"), Message: "abc"},
+			want:  "Jan-01-2000 a.go:1     INFO:  abc\n",
+		},
+		{
+			name:  "no label",
+			enc:   PrettyEncoder(ConsoleLevelWidth(6)),
+			entry: Entry{Stamp: []byte("Jan-01-2000"), Message: "abc"},
+			want:  "Jan-01-2000        abc\n",
+		},
+		{
+			name:  "field lines",
+			enc:   PrettyEncoder(ConsoleFieldLines(1)),
+			entry: Entry{Label: []byte("INFO:"), Message: "abc", Fields: []KeyVal{{"a", 1}, {"b", "x y"}}},
+			want:  "INFO: abc\n      a=1\n      b=\"x y\"\n",
+		},
+		{
+			name:  "few fields",
+			enc:   PrettyEncoder(),
+			entry: Entry{Label: []byte("INFO:"), Message: "abc", Fields: []KeyVal{{"a", 1}, {"b", 2}}},
+			want:  "INFO: abc a=1 b=2\n",
+		},
+		{
+			name:  "multi-line",
+			enc:   PrettyEncoder(),
+			entry: Entry{Label: []byte("ERROR:"), Message: "abc\ndef", Fields: []KeyVal{{"a", 1}, {"stack", "main.go:1\nrun.go:2\n"}}},
+			want:  "ERROR: abc\n       def a=1\n       stack=\n           main.go:1\n           run.go:2\n",
+		},
+		{
+			name:  "console",
+			enc:   ConsoleEncoder(ConsoleFieldLines(1)),
+			entry: Entry{Label: []byte("INFO:"), Message: "abc\ndef", Fields: []KeyVal{{"a", 1}, {"b", "x\ny"}}},
+			want:  "INFO: abc\ndef\n      a=1\n      b=\"x\\ny\"\n",
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.enc.Encode(have, &test.entry)
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+
+	t.Run("auto widths", func(tt *testing.T) {
+		enc := PrettyEncoder()
+		have.Reset()
+		for _, label := range []string{"WARN:", "ERROR:", "INFO:"} {
+			enc.Encode(have, &Entry{Caller: "abc.go:10", Label: []byte(label), Message: "abc"})
+		}
+		enc.Encode(have, &Entry{Caller: "a.go:1", Label: []byte("INFO:"), Message: "abc"})
+
+		want := "abc.go:10 WARN: abc\nabc.go:10 ERROR: abc\nabc.go:10 INFO:  abc\na.go:1    INFO:  abc\n"
+		if have.String() != want {
+			tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), want)
+		}
+	})
+}
+
+var (
+	audit    = MustRegisterLevel("Audit", "aud", color.Cyan, 55)
+	security = MustRegisterLevel("security", "", color.NoColor, 65)
+)
+
+func TestRegisterLevel(t *testing.T) {
+	have := new(bytes.Buffer)
+	newLog := func(opts ...optFunc) Logger {
+		return New(append([]optFunc{WithOutput(have), WithTimeText("Jan-01-2000")}, opts...)...)
+	}
+
+	tests := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{
+			name: "log.Log",
+			log:  func() { newLog().Log(audit, "abc", KV("the", "quick")) },
+			want: "Jan-01-2000 \x1b[36mAUDIT: abc\x1b[0m the=quick\n",
+		},
+		{
+			name: "log.Logf no color",
+			log:  func() { newLog().Logf(security, "%s-%d", "abc", 1) },
+			want: "Jan-01-2000 SECURITY: abc-1\n",
+		},
+		{
+			name: "log.Logln built-in level",
+			log:  func() { newLog().Logln(Warn, "abc", "def") },
+			want: "Jan-01-2000 \x1b[33mWARN: abc def\x1b[0m\n",
+		},
+		{
+			name: "log.Logt",
+			log:  func() { newLog(WithOutput(noColorWriter{have})).Logt(audit, "user {user}", "someone") },
+			want: "Jan-01-2000 AUDIT: user someone template=user {user}, user=someone\n",
+		},
+		{
+			name: "log.Event",
+			log:  func() { newLog(WithOutput(noColorWriter{have})).Event(security).Int("n", 1).Msg("abc") },
+			want: "Jan-01-2000 SECURITY: abc n=1\n",
+		},
+		{
+			name: "log.Log JSON",
+			log:  func() { newLog(WithEncoder(JSONEncoder()), withTime(jsonTime)).Log(audit, "abc") },
+			want: `{"time":"2000-01-01T00:00:00Z","level":"audit","msg":"abc"}` + "\n",
+		},
+		{
+			name: "log.Log Suppress",
+			log:  func() { newLog().Suppress(audit).Log(audit, "abc") },
+			want: "",
+		},
+		{
+			name: "log.Log SetLevel",
+			log: func() {
+				log := newLog(WithOutput(noColorWriter{have})).SetLevel(Error)
+				log.Log(audit, "abc")
+				log.Log(security, "def")
+			},
+			want: "Jan-01-2000 SECURITY: def\n",
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.log()
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+
+	if string(security.levelize(3)) != "[SEC]" {
+		t.Errorf("\nhave: %q\nwant: %q\n", security.levelize(3), "[SEC]")
+	}
+	for _, name := range []string{"audit", "INFO", " "} {
+		if _, err := RegisterLevel(name, "", color.NoColor, 0); err == nil {
+			t.Errorf("\nhave: <nil>\nwant: an error for %q\n", name)
+		}
+	}
+}
+
+func TestReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	have := new(bytes.Buffer)
+	log := New(WithOutput(have, FilterWriter(FileWriter(path))), WithTimeText("Jan-01-2000"))
+
+	log.Info("one")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	log.Info("two") // still written to the moved file
+	if err := log.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	log.Info("three")
+
+	for file, want := range map[string]string{
+		path + ".1": "Jan-01-2000 INFO: one\nJan-01-2000 INFO: two\n",
+		path:        "Jan-01-2000 INFO: three\n",
+	} {
+		b, _ := ioutil.ReadFile(file)
+		if string(b) != want {
+			t.Errorf("\nhave: %q\nwant: %q\n", b, want)
+		}
+	}
+}
+
+func TestReopenAfterError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a file where the directory should be, so the log file can't be opened
+	blocked := filepath.Join(dir, "logs")
+	if err := ioutil.WriteFile(blocked, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(blocked, "app.log")
+	fw := FileWriter(path).(*fileWriter)
+	if err := fw.Reopen(); err == nil {
+		t.Fatal("\nhave: <nil>\nwant: an error\n")
+	}
+
+	if err := os.Remove(blocked); err != nil {
+		t.Fatal(err)
+	}
+	if err := fw.Reopen(); err != nil {
+		t.Fatalf("\nhave: %v\nwant: <nil>\n", err)
+	}
+	fw.Write([]byte("one\n"))
+	if err := fw.Close(); err != nil {
+		t.Fatalf("\nhave: %v\nwant: <nil>\n", err)
+	}
+
+	if b, _ := ioutil.ReadFile(path); string(b) != "one\n" {
+		t.Errorf("\nhave: %q\nwant: %q\n", b, "one\n")
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	have := new(bytes.Buffer)
+	newLog := func() Logger { return New(WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000")) }
+
+	tests := []struct {
+		name    string
+		log     Logger
+		enabled []logLevel
+		want    string
+	}{
+		{
+			name:    "all levels",
+			log:     newLog(),
+			enabled: []logLevel{Trace, Debug, Info, Warn, Error, Fatal, Panic},
+			want:    "Jan-01-2000 TRACE: a\nJan-01-2000 DEBUG: b\nJan-01-2000 c\nJan-01-2000 INFO: d\nJan-01-2000 WARN: e\nJan-01-2000 ERROR: f\n",
+		},
+		{
+			name:    "SetLevel Warn",
+			log:     newLog().SetLevel(Warn),
+			enabled: []logLevel{Warn, Error, Fatal, Panic},
+			want:    "Jan-01-2000 WARN: e\nJan-01-2000 ERROR: f\n",
+		},
+		{
+			name:    "SetLevel Debug and Suppress Info",
+			log:     newLog().SetLevel(Debug).Suppress(Info),
+			enabled: []logLevel{Debug, Warn, Error, Fatal, Panic},
+			want:    "Jan-01-2000 DEBUG: b\nJan-01-2000 WARN: e\nJan-01-2000 ERROR: f\n",
+		},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.log.Trace("a")
+			test.log.Debug("b")
+			test.log.Print("c")
+			test.log.Info("d")
+			test.log.Warn("e")
+			test.log.Error("f")
+
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+
+			var enabled []logLevel
+			for _, level := range []logLevel{Trace, Debug, Info, Warn, Error, Fatal, Panic} {
+				if test.log.Enabled(level) {
+					enabled = append(enabled, level)
+				}
+			}
+			if !reflect.DeepEqual(enabled, test.enabled) {
+				tt.Errorf("\nhave: %v\nwant: %v\n", enabled, test.enabled)
+			}
+		})
+	}
+}
+
+func TestSingleWrite(t *testing.T) {
+	pieces := EncoderFunc(func(w io.Writer, e *Entry) error {
+		for _, p := range []string{string(e.Stamp), " ", string(e.Color), e.Message, "\n"} {
+			if _, err := io.WriteString(w, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name string
+		opts []optFunc
+		want []string // the writes to the color writer then the no color writer
+	}{
+		{
+			name: "text",
+			want: []string{"Jan-01-2000 \x1b[32mINFO: abc\x1b[0m the=quick\n", "Jan-01-2000 INFO: abc the=quick\n"},
+		},
+		{
+			name: "json",
+			opts: []optFunc{WithEncoder(JSONEncoder()), withTime(jsonTime)},
+			want: []string{`{"time":"2000-01-01T00:00:00Z","level":"info","msg":"abc","the":"quick"}` + "\n", `{"time":"2000-01-01T00:00:00Z","level":"info","msg":"abc","the":"quick"}` + "\n"},
+		},
+		{
+			name: "encoder with many writes",
+			opts: []optFunc{WithEncoder(pieces)},
+			want: []string{"Jan-01-2000 \x1b[32mabc\n", "Jan-01-2000 abc\n"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(tt *testing.T) {
+			var have []string
+			cw := writerFunc(func(p []byte) (int, error) { have = append(have, string(p)); return len(p), nil })
+			nw := noColorWriter{writerFunc(func(p []byte) (int, error) { have = append(have, string(p)); return len(p), nil })}
+
+			log := New(append([]optFunc{WithOutput(cw, nw), WithTimeText("Jan-01-2000")}, test.opts...)...)
+			log.Info("abc", KV("the", "quick"))
+
+			if !reflect.DeepEqual(have, test.want) {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have, test.want)
+			}
+		})
+	}
+}
+
 func TestSupress(t *testing.T) {
 	have := new(bytes.Buffer)
-	log := New(WithOutput(have), WithTimeText("Jan-01-2000"))
+	newLog := func() Logger { return New(WithOutput(have), WithTimeText("Jan-01-2000")) }
 
 	tests := []struct {
-		name     string
-		suppress logLevel
-		want     string
+		name string
+		log  Logger
+		want string
 	}{
 		{
-			name:     "Suppress Info",
-			suppress: Info,
-			want:     "Jan-01-2000 \x1b[33mWARN: Pack my box with five dozen liquor jugs\x1b[0m\nJan-01-2000 \x1b[36mDEBUG: Cozy lummox gives smart squid who asks for job pen\x1b[0m\n",
+			name: "Suppress Info",
+			log:  newLog().Suppress(Info),
+			want: "Jan-01-2000 \x1b[33mWARN: Pack my box with five dozen liquor jugs\x1b[0m\nJan-01-2000 \x1b[36mDEBUG: Cozy lummox gives smart squid who asks for job pen\x1b[0m\n",
+		},
+		{
+			name: "Suppress Debug and Warn",
+			log:  newLog().Suppress(Debug | Warn),
+			want: "Jan-01-2000 \x1b[32mINFO: The quick brown fox jumped over the lazy dog\x1b[0m\n",
+		},
+		{
+			name: "Suppress Debug then Warn",
+			log:  newLog().Suppress(Debug).Suppress(Warn),
+			want: "Jan-01-2000 \x1b[32mINFO: The quick brown fox jumped over the lazy dog\x1b[0m\n",
 		},
 		{
-			name:     "Suppress Debug and Warn",
-			suppress: Debug | Warn,
-			want:     "Jan-01-2000 \x1b[32mINFO: The quick brown fox jumped over the lazy dog\x1b[0m\n",
+			name: "Unsuppress Warn",
+			log:  newLog().Suppress(Info | Debug | Warn).Unsuppress(Warn),
+			want: "Jan-01-2000 \x1b[33mWARN: Pack my box with five dozen liquor jugs\x1b[0m\n",
 		},
 	}
 
 	for _, test := range tests {
 		have.Reset()
 		t.Run(test.name, func(tt *testing.T) {
+			test.log.Info("The quick brown fox jumped over the lazy dog")
+			test.log.Warn("Pack my box with five dozen liquor jugs")
+			test.log.Debug("Cozy lummox gives smart squid who asks for job pen")
+
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestSyslog(t *testing.T) {
+	want := map[logLevel]int{
+		Emergency: 0, Panic: 0, Alert: 1, Critical: 2, Fatal: 2, security: 3, Error: 3,
+		audit: 4, Warn: 4, Notice: 5, Info: 6, HTTP: 6, Debug: 7, Trace: 7,
+	}
+	for level, want := range want {
+		if have := level.Syslog(); have != want {
+			t.Errorf("%s\nhave: %d\nwant: %d\n", level, have, want)
+		}
+	}
+
+	have := new(bytes.Buffer)
+	log := New(WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000")).SetLevel(Notice)
+	log.Info("abc")
+	log.Notice("def")
+	log.Criticalf("%s", "ghi")
+	log.OnErr(bytes.ErrTooLarge).Alertln("jkl")
+	log.Emergencyt("{mno}", 1)
+
+	want2 := "Jan-01-2000 NOTICE: def\nJan-01-2000 CRITICAL: ghi\nJan-01-2000 ALERT: jkl\nJan-01-2000 EMERGENCY: 1 mno=1, template={mno}\n"
+	if have.String() != want2 {
+		t.Errorf("\nhave: %q\nwant: %q\n", have.String(), want2)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	have := new(bytes.Buffer)
+	log := New(WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000"))
 
-			log.Suppress(test.suppress)
-			log.Info("The quick brown fox jumped over the lazy dog")
-			log.Warn("Pack my box with five dozen liquor jugs")
-			log.Debug("Cozy lummox gives smart squid who asks for job pen")
+	tests := []struct {
+		name   string
+		method func(string, ...interface{})
+		format string
+		input  []interface{}
+		want   string
+	}{
+		{
+			name:   "log.Infot",
+			method: log.Infot,
+			format: "user {user} bought {count} items",
+			input:  []interface{}{"someone", 3},
+			want:   "Jan-01-2000 INFO: user someone bought 3 items count=3, template=user {user} bought {count} items, user=someone\n",
+		},
+		{
+			name:   "log.Printt KV between values",
+			method: log.Printt,
+			format: "{a} and {b}",
+			input:  []interface{}{1, KV("the", "quick"), 2},
+			want:   "Jan-01-2000 1 and 2 a=1, b=2, template={a} and {b}, the=quick\n",
+		},
+		{
+			name:   "log.Printt KV with a placeholder key",
+			method: log.Printt,
+			format: "user {id}",
+			input:  []interface{}{5, KV("id", 7)},
+			want:   "Jan-01-2000 user 5 id=7, template=user {id}\n",
+		},
+		{
+			name:   "log.Printt placeholder from a Field",
+			method: log.With().Field("id", 7).Printt,
+			format: "user {id}",
+			input:  []interface{}{},
+			want:   "Jan-01-2000 user 7 id=7, template=user {id}\n",
+		},
+		{
+			name:   "log.Printt missing values",
+			method: log.Printt,
+			format: "{a} {b}",
+			input:  []interface{}{1},
+			want:   "Jan-01-2000 1 {b} a=1, template={a} {b}\n",
+		},
+		{
+			name:   "log.Printt extra values",
+			method: log.Printt,
+			format: "{a}",
+			input:  []interface{}{1, 2, "three"},
+			want:   "Jan-01-2000 1 2 three a=1, template={a}\n",
+		},
+		{
+			name:   "log.Printt braces",
+			method: log.Printt,
+			format: "{{a}} {a} {a} { b} {c",
+			input:  []interface{}{1, 2},
+			want:   "Jan-01-2000 {a} 1 1 { b} {c 2 a=1, template={{a}} {a} {a} { b} {c\n",
+		},
+		{
+			name:   "log.Warnt ordered",
+			method: New(WithOutput(noColorWriter{have}), WithTimeText("Jan-01-2000"), WithOrderedFields()).Warnt,
+			format: "user {user} bought {count} items",
+			input:  []interface{}{"someone", 3},
+			want:   "Jan-01-2000 WARN: user someone bought 3 items user=someone, count=3, template=user {user} bought {count} items\n",
+		},
+		{
+			name:   "log.Errort JSON",
+			method: New(WithOutput(have), WithTimeText("Jan-01-2000"), WithEncoder(JSONEncoder()), withTime(jsonTime)).Errort,
+			format: "user {user} failed after {took}",
+			input:  []interface{}{"someone", 1500 * time.Millisecond},
+			want:   `{"time":"2000-01-01T00:00:00Z","level":"error","msg":"user someone failed after 1.5s","template":"user {user} failed after {took}","took":"1.5s","user":"someone"}` + "\n",
+		},
+		{
+			name: "log.OnErr.Debugt",
+			method: func(format string, v ...interface{}) {
+				log.OnErr(bytes.ErrTooLarge).Debugt(format, v...)
+			},
+			format: "{err}",
+			input:  []interface{}{ErrSub{}},
+			want:   "Jan-01-2000 DEBUG: bytes.Buffer: too large err=bytes.Buffer: too large, template={err}\n",
+		},
+	}
 
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			test.method(test.format, test.input...)
 			if have.String() != test.want {
 				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
 			}
@@ -1413,6 +3126,39 @@ func TestTime(t *testing.T) {
 	}
 }
 
+func TestWithFields(t *testing.T) {
+	have := new(bytes.Buffer)
+
+	tests := []struct {
+		name string
+		opts []optFunc
+		want string
+	}{
+		{name: "unordered", want: "parent k=2, p=1\nchild c=1, k=1, p=1\n"},
+		{name: "ordered", opts: []optFunc{WithOrderedFields()}, want: "parent p=1, k=2\nchild p=1, k=1, c=1\n"},
+	}
+
+	for _, test := range tests {
+		have.Reset()
+		t.Run(test.name, func(tt *testing.T) {
+			parent := New(append([]optFunc{WithOutput(have), WithTimeText("")}, test.opts...)...).Field("p", 1)
+			child := parent.With()
+			child.Field("k", 1)
+			parent.Field("k", 2) // set after the child was created
+			child.Field("c", 1)  // a key the parent doesn't have
+
+			parent.Print("parent")
+			child.Print("child")
+			if have.String() != test.want {
+				tt.Errorf("\nhave: %q\nwant: %q\n", have.String(), test.want)
+			}
+		})
+	}
+}
+
+// jsonTime is the time for the JSONEncoder tests, which write the time and not the time text
+var jsonTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
 func withTime(t time.Time) optFunc {
 	return func(b *baseLogger) {
 		b.ts.now = t
@@ -1423,6 +3169,14 @@ func randomDuration() time.Duration {
 	return time.Duration(rand.Intn(10)) * time.Millisecond
 }
 
+type noColorWriter struct{ io.Writer }
+
+type writerFunc func([]byte) (int, error)
+
+func (fn writerFunc) Write(p []byte) (int, error) { return fn(p) }
+
+func (noColorWriter) NoColor() {}
+
 func errorMarshal(v interface{}) ([]byte, error) {
 	return nil, bytes.ErrTooLarge
 }
@@ -1430,7 +3184,9 @@ func errorMarshal(v interface{}) ([]byte, error) {
 func serveTCP(port string, have chan string, setup chan net.Listener, teardown chan struct{}, t *testing.T) {
 	listener, err := net.Listen("tcp", port)
 	if err != nil {
-		t.Fatal(err)
+		t.Error(err)
+		close(setup)
+		return
 	}
 	setup <- listener
 	for {
@@ -19,8 +19,9 @@ func (e *onErrLogger) Flags() int {
 }
 
 func (e *onErrLogger) Output(calldepth int, s string) error {
-	e.b.depth = calldepth
-	e.b.Println(s)
+	if e.b.Enabled(Info) {
+		e.b.print(bPrintln, []interface{}{s}, depthize(calldepth))
+	}
 	return nil //TODO(njones): pass the error along...
 }
 
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/njones/logger/color"
+	"github.com/njones/logger/kv"
 )
 
 // All of the flags from the std log pkg
@@ -18,6 +19,23 @@ const (
 	LstdFlags     = Ldate | Ltime // initial values for the standard logger
 )
 
+// The caller flags that are not in the std log pkg, they start after the std log Lmsgprefix flag
+const (
+	Lrelfile  = LUTC << (iota + 2) // file name relative to the module root and line number: internal/db/pool.go:23. overrides Llongfile and Lshortfile
+	Lfuncname                      // the package and name of the calling function: db.(*Pool).Get
+	Lpackage                       // the import path of the calling package: github.com/a/b/internal/db
+)
+
+// The built-in level display styles used by WithLevelDisplay
+const (
+	DisplayDefault  levelDisplay = iota // INFO:
+	DisplayBox                          // [INFO]
+	DisplayShort                        // INF:
+	DisplayShortBox                     // [INF]
+)
+
+type levelDisplay int
+
 // WithColor adds color to the logged output (overriding any level colors)
 func WithColor(v color.Foreground) optFunc {
 	var escColor = []byte(v.ToESC())
@@ -26,6 +44,13 @@ func WithColor(v color.Foreground) optFunc {
 	}
 }
 
+// WithEncoder uses enc to write each log line instead of the default text layout
+func WithEncoder(enc Encoder) optFunc {
+	return func(b *baseLogger) {
+		b.enc = enc
+	}
+}
+
 // WithHTTPHeader takes headers and addes them as a structured K/V pair to the logged output
 func WithHTTPHeader(headers ...string) optFunc {
 	return func(b *baseLogger) {
@@ -42,6 +67,56 @@ func WithHTTPHeader(headers ...string) optFunc {
 func WithKVMarshaler(fn func(interface{}) ([]byte, error)) optFunc {
 	return func(b *baseLogger) {
 		b.kv.marshal = fn
+		b.kv.append = kv.AppenderOf(fn)
+	}
+}
+
+// WithLayout sets the order of the parts of each line written by the default encoder. The
+// parts are the placeholders {time}, {caller}, {level}, {prefix}, {msg} and {fields}, anything
+// else is written as is. A space separated word with only empty placeholders is left out, so
+// "[{caller}]" is only written when there is a caller. See DefaultLayout for the default.
+func WithLayout(format string) optFunc {
+	var l = parseLayout(format)
+	return func(b *baseLogger) {
+		b.layout = l
+	}
+}
+
+// WithLevelColors sets the color for each level in the map, any other levels keep
+// their default color. A zero Style turns off the color for the level.
+func WithLevelColors(styles map[logLevel]color.Style) optFunc {
+	var colors = make(map[logLevel]colorize, len(styles))
+	for level, style := range styles {
+		colors[level] = colorize(style.ToESC())
+	}
+	return func(b *baseLogger) {
+		b.colors = colors
+	}
+}
+
+// WithLevelDisplay uses one of the built-in styles for the level labels
+func WithLevelDisplay(display levelDisplay) optFunc {
+	return func(b *baseLogger) {
+		b.display = int(display)
+	}
+}
+
+// WithLevelLabels uses fn for the level labels instead of a built-in style, i.e. to pad
+// the labels to the same width, or use an emoji for each level. The label is used by every
+// encoder (the JSON encoder uses it trimmed as the level), and an empty label leaves the level
+// label out of the line. The func is called once for each level and the label is reused.
+func WithLevelLabels(fn func(logLevel) string) optFunc {
+	return func(b *baseLogger) {
+		b.labels = &levelLabels{fn: fn}
+	}
+}
+
+// WithOrderedFields keeps the structured K/V pairs in the order they were added, rather
+// than sorting them by key. Any Field(s) values come first followed by the K/V values
+// passed in to the log function. A duplicate key keeps its first place with the last value.
+func WithOrderedFields() optFunc {
+	return func(b *baseLogger) {
+		b.kv.ordered = true
 	}
 }
 
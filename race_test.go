@@ -0,0 +1,7 @@
+//go:build race
+// +build race
+
+package logger
+
+// raceEnabled is true when the race detector is on, it adds allocations
+const raceEnabled = true
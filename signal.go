@@ -0,0 +1,39 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// WithReopenOnHUP reopens the writers (see Reopen) each time the process gets a SIGHUP, i.e.
+// from the `postrotate kill -HUP` of logrotate. Any error from reopening is written to stderr.
+func WithReopenOnHUP() optFunc {
+	return func(b *baseLogger) {
+		if b.out.hup != nil {
+			return
+		}
+		var ch = make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGHUP)
+		go func() {
+			for range ch {
+				if err := b.Reopen(); err != nil {
+					stderr(err)
+				}
+			}
+		}()
+		var once sync.Once
+		b.out.hup = closerFunc(func() error {
+			once.Do(func() {
+				signal.Stop(ch)
+				close(ch)
+			})
+			return nil
+		})
+		b.out.close.add(b.out.hup)
+	}
+}
@@ -0,0 +1,76 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReopenOnHUP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	log := New(WithOutput(FileWriter(path)), WithTimeText("Jan-01-2000"), WithReopenOnHUP()).(*baseLogger)
+	defer log.out.hup.Close()
+
+	log.Info("one")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	log.Info("two")
+
+	b, _ := ioutil.ReadFile(path)
+	if have, want := string(b), "Jan-01-2000 INFO: two\n"; have != want {
+		t.Errorf("\nhave: %q\nwant: %q\n", have, want)
+	}
+}
+
+func TestReopenOnHUPWith(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	parent := New(WithOutput(ioutil.Discard), WithReopenOnHUP()).(*baseLogger)
+	defer parent.out.hup.Close()
+
+	path := filepath.Join(dir, "app.log")
+	log := parent.With(WithOutput(FileWriter(path)), WithTimeText("Jan-01-2000"), WithReopenOnHUP()).(*baseLogger)
+	defer log.Close()
+
+	log.Info("one")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	log.Info("two")
+
+	b, _ := ioutil.ReadFile(path)
+	if have, want := string(b), "Jan-01-2000 INFO: two\n"; have != want {
+		t.Errorf("\nhave: %q\nwant: %q\n", have, want)
+	}
+}
@@ -0,0 +1,6 @@
+package logger
+
+// WithReopenOnHUP does nothing on windows, there is no SIGHUP
+func WithReopenOnHUP() optFunc {
+	return func(*baseLogger) {}
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/njones/logger/kv"
+)
+
+// TemplateKey is the field that holds the raw message template used by the
+// Print(t) style functions, so lines can be grouped by their template
+const TemplateKey = "template"
+
+// templatize turns the values for the template placeholders into K/V pairs (in order), so
+// they are filtered with all of the other K/V pairs, then adds the raw template. Any
+// KeyVal or KVMap values are skipped over, and any extra values are kept for the message.
+// i.e. Infot("user {user} bought {count} items", u, 3) adds user=u, count=3 and the template.
+// The placeholder pairs are also appended to places, so the message is written with them
+// even when a K/V pair with the same key replaces the field.
+func templatize(tmpl string, v []interface{}, places []KeyVal) ([]interface{}, []KeyVal) {
+	var i int
+	var out = make([]interface{}, 0, len(v)+1)
+
+	holes(tmpl, nil, func(name string) {
+		for _, p := range places {
+			if string(p.Key) == name {
+				return // a repeated placeholder uses the first value
+			}
+		}
+		for ; i < len(v); i++ {
+			if isKV(v[i]) {
+				out = append(out, v[i])
+				continue
+			}
+			var p = KeyVal{K(name), v[i]}
+			out, places = append(out, p), append(places, p)
+			i++
+			return
+		}
+	})
+
+	out = append(out, v[i:]...)
+	return append(out, KeyVal{TemplateKey, tmpl}), places
+}
+
+func isKV(v interface{}) bool {
+	switch v.(type) {
+	case KeyVal, KVMap, map[K]V:
+		return true
+	}
+	return false
+}
+
+// template writes the message using the placeholder values, then any values for the
+// placeholders that are in the K/V pairs (i.e. from a Field), a placeholder without a
+// value is written as is. Any extra values are added with a space.
+func (ln *line) template() {
+	holes(ln.format, func(text string) { ln.msg.WriteString(text) }, func(name string) {
+		for _, p := range ln.places {
+			if string(p.Key) == name {
+				ln.templateValue(resolve(p.Value))
+				return
+			}
+		}
+		for _, p := range ln.kv {
+			if string(p.Key) == name {
+				ln.templateValue(p.Value)
+				return
+			}
+		}
+		ln.msg.WriteString("{" + name + "}")
+	})
+
+	for _, v := range ln.v {
+		ln.msg.WriteByte(' ')
+		fmt.Fprint(&ln.msg, v)
+	}
+}
+
+// templateValue writes a placeholder value to the message
+func (ln *line) templateValue(v interface{}) {
+	if s, ok := kv.Render(v); ok {
+		ln.msg.WriteString(s)
+		return
+	}
+	fmt.Fprint(&ln.msg, v)
+}
+
+// holes calls text for each literal part of the template and hole for each {name}
+// placeholder. A doubled `{{` or `}}` is a single brace, and a brace that does
+// not start a valid placeholder is kept as text. A nil text func is skipped.
+func holes(tmpl string, text func(string), hole func(string)) {
+	if text == nil {
+		text = func(string) {}
+	}
+
+	for len(tmpl) > 0 {
+		i := strings.IndexAny(tmpl, "{}")
+		if i < 0 {
+			text(tmpl)
+			return
+		}
+		if i+1 < len(tmpl) && tmpl[i+1] == tmpl[i] {
+			text(tmpl[:i+1])
+			tmpl = tmpl[i+2:]
+			continue
+		}
+		if tmpl[i] == '}' {
+			text(tmpl[:i+1])
+			tmpl = tmpl[i+1:]
+			continue
+		}
+
+		j := strings.IndexByte(tmpl[i:], '}')
+		if j < 0 || !isHoleName(tmpl[i+1:i+j]) {
+			text(tmpl[:i+1])
+			tmpl = tmpl[i+1:]
+			continue
+		}
+
+		text(tmpl[:i])
+		hole(tmpl[i+1 : i+j])
+		tmpl = tmpl[i+j+1:]
+	}
+}
+
+// isHoleName returns true for a non-empty name of letters, digits, `_`, `.` or `-`
+func isHoleName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '_', r == '.', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -1,20 +1,43 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"net/http"
 )
 
+// NoColorWriter is a marker for a writer that never gets color, even with FORCE_COLOR. Without
+// it, only a terminal gets color, and NO_COLOR or FORCE_COLOR turn color off or on.
 type NoColorWriter interface {
 	NoColor()
 }
 
+// Reopener is a writer that can close and open its file again, i.e. after the file
+// has been moved by an external logrotate
+type Reopener interface {
+	Reopen() error
+}
+
+// BufferedWriter is a writer that holds on to the writes before writing them, i.e. an AsyncWriter
+type BufferedWriter interface {
+	io.WriteCloser
+	Flush(context.Context) error
+	Dropped() uint64
+}
+
+// unwrapper is a writer that wraps another writer, i.e. a FilterWriter or AsyncWriter
+type unwrapper interface {
+	unwrap() io.Writer
+}
+
 type printKind int
 
 const (
 	bPrint printKind = iota
 	bPrintf
 	bPrintln
+	bPrintt
+	bMsg // the message is used as is
 )
 
 type optFunc func(*baseLogger)
@@ -32,10 +55,21 @@ type Logger interface {
 
 	OnErr(error) OnErrLogger
 
+	Close() error
+	Enabled(logLevel) bool
+	Event(logLevel) *Event
 	FatalInt(int) Logger
 	Field(string, interface{}) Logger
 	Fields(map[string]interface{}) Logger
+	Log(logLevel, ...interface{})
+	Logf(logLevel, string, ...interface{})
+	Logln(logLevel, ...interface{})
+	Logt(logLevel, string, ...interface{})
+	Reopen() error
+	SetLevel(logLevel) Logger
 	Suppress(logLevel) Logger
+	Sync() error
+	Unsuppress(logLevel) Logger
 	With(...optFunc) Logger
 }
 
@@ -71,21 +105,46 @@ type StandardLogger interface {
 type ExtendedLogger interface {
 	StandardLogger
 
+	Printt(template string, v ...interface{})
+	Fatalt(template string, v ...interface{})
+	Panict(template string, v ...interface{})
+
 	Info(v ...interface{})
 	Infof(format string, v ...interface{})
 	Infoln(v ...interface{})
+	Infot(template string, v ...interface{})
 	Warn(v ...interface{})
 	Warnf(format string, v ...interface{})
 	Warnln(v ...interface{})
+	Warnt(template string, v ...interface{})
 	Error(v ...interface{})
 	Errorf(format string, v ...interface{})
 	Errorln(v ...interface{})
+	Errort(template string, v ...interface{})
 	Debug(v ...interface{})
 	Debugf(format string, v ...interface{})
 	Debugln(v ...interface{})
+	Debugt(template string, v ...interface{})
 	Trace(v ...interface{})
 	Tracef(format string, v ...interface{})
 	Traceln(v ...interface{})
+	Tracet(template string, v ...interface{})
+	Notice(v ...interface{})
+	Noticef(format string, v ...interface{})
+	Noticeln(v ...interface{})
+	Noticet(template string, v ...interface{})
+	Critical(v ...interface{})
+	Criticalf(format string, v ...interface{})
+	Criticalln(v ...interface{})
+	Criticalt(template string, v ...interface{})
+	Alert(v ...interface{})
+	Alertf(format string, v ...interface{})
+	Alertln(v ...interface{})
+	Alertt(template string, v ...interface{})
+	Emergency(v ...interface{})
+	Emergencyf(format string, v ...interface{})
+	Emergencyln(v ...interface{})
+	Emergencyt(template string, v ...interface{})
 }
 
 // HTTPLogger the interface that defines HTTP logging that
@@ -101,33 +160,64 @@ type OnErrLogger interface {
 	Print(v ...interface{}) Return
 	Printf(format string, v ...interface{}) Return
 	Println(v ...interface{}) Return
+	Printt(template string, v ...interface{}) Return
 	Fatal(v ...interface{}) Return
 	Fatalf(format string, v ...interface{}) Return
 	Fatalln(v ...interface{}) Return
+	Fatalt(template string, v ...interface{}) Return
 	Panic(v ...interface{}) Return
 	Panicf(format string, v ...interface{}) Return
 	Panicln(v ...interface{}) Return
+	Panict(template string, v ...interface{}) Return
 
 	// from the extended logger
 	Info(v ...interface{}) Return
 	Infof(format string, v ...interface{}) Return
 	Infoln(v ...interface{}) Return
+	Infot(template string, v ...interface{}) Return
 	Warn(v ...interface{}) Return
 	Warnf(format string, v ...interface{}) Return
 	Warnln(v ...interface{}) Return
+	Warnt(template string, v ...interface{}) Return
 	Error(v ...interface{}) Return
 	Errorf(format string, v ...interface{}) Return
 	Errorln(v ...interface{}) Return
+	Errort(template string, v ...interface{}) Return
 	Debug(v ...interface{}) Return
 	Debugf(format string, v ...interface{}) Return
 	Debugln(v ...interface{}) Return
+	Debugt(template string, v ...interface{}) Return
 	Trace(v ...interface{}) Return
 	Tracef(format string, v ...interface{}) Return
 	Traceln(v ...interface{}) Return
+	Tracet(template string, v ...interface{}) Return
+	Notice(v ...interface{}) Return
+	Noticef(format string, v ...interface{}) Return
+	Noticeln(v ...interface{}) Return
+	Noticet(template string, v ...interface{}) Return
+	Critical(v ...interface{}) Return
+	Criticalf(format string, v ...interface{}) Return
+	Criticalln(v ...interface{}) Return
+	Criticalt(template string, v ...interface{}) Return
+	Alert(v ...interface{}) Return
+	Alertf(format string, v ...interface{}) Return
+	Alertln(v ...interface{}) Return
+	Alertt(template string, v ...interface{}) Return
+	Emergency(v ...interface{}) Return
+	Emergencyf(format string, v ...interface{}) Return
+	Emergencyln(v ...interface{}) Return
+	Emergencyt(template string, v ...interface{}) Return
 }
 
 type Errer interface{ Err() error }
 
+// LogValuer is implemented by any value that returns what should be logged in its
+// place. The value is resolved only when the line is written, so an expensive value
+// costs nothing on a suppressed level, and a value can redact itself (i.e. a password).
+type LogValuer interface {
+	LogValue() interface{}
+}
+
 // The Key-Value types
 type (
 	K string